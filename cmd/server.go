@@ -11,8 +11,10 @@ import (
 	"h-ui/router"
 	"h-ui/service"
 	"h-ui/util"
+	"net"
 	"net/http"
 	"os"
+	"strconv"
 )
 
 func runServer(port string) error {
@@ -48,6 +50,9 @@ func runServer(port string) error {
 	}
 
 	r := gin.Default()
+	if err := service.InitClientIP(r); err != nil {
+		return err
+	}
 	router.Router(r, config.Value)
 
 	serverPort, crtPath, keyPath, err := service.GetServerPortAndCert()
@@ -55,7 +60,11 @@ func runServer(port string) error {
 		return err
 	}
 
-	service.InitServer(fmt.Sprintf(":%d", serverPort), r)
+	bindAddress, unixSocket, err := service.GetServerListen()
+	if err != nil {
+		return err
+	}
+	service.InitServer(net.JoinHostPort(bindAddress, strconv.FormatInt(serverPort, 10)), unixSocket, r)
 	if err := service.StartServer(crtPath, keyPath); err != nil && err != http.ErrServerClosed {
 		logrus.Errorf("start server err: %v", err)
 		return errors.New("start server err")
@@ -76,7 +85,7 @@ func releaseResource() {
 }
 
 func initFile() error {
-	var dirs = []string{constant.LogDir, constant.SqliteDBDir, constant.BinDir, constant.ExportPathDir}
+	var dirs = []string{constant.LogDir, constant.SqliteDBDir, constant.BinDir, constant.ExportPathDir, constant.CertDir}
 	for _, item := range dirs {
 		if !util.Exists(item) {
 			if err := os.Mkdir(item, os.ModePerm); err != nil {
@@ -30,24 +30,82 @@ func Login(c *gin.Context) {
 
 	token, err := service.Login(*loginDto.Username, util.SHA224String(*loginDto.Pass))
 	if err != nil {
+		service.SaveLoginHistory(*loginDto.Username, c.ClientIP(), constant.LoginFailed)
 		vo.Fail(err.Error(), c)
 		return
 	}
+	if service.Telegram2FAEnabled(*loginDto.Username) {
+		// 开启 2FA 后 token 需要在 Telegram 审批通过后通过 loginStatus 获取
+		pendingId, err := service.RequestLoginApproval(*loginDto.Username, c.ClientIP(), token)
+		if err != nil {
+			logrus.Warnf("telegram login approval failed: %v", err)
+			c.JSON(http.StatusServiceUnavailable, gin.H{"code": "tg_unavailable"})
+			return
+		}
+		vo.Success(vo.JwtVo{
+			TokenType: constant.TokenType,
+			PendingId: pendingId,
+		}, c)
+		return
+	}
 	jwtVo := vo.JwtVo{
 		TokenType:   constant.TokenType,
 		AccessToken: token,
 	}
 	if err := service.TelegramLoginRemind(*loginDto.Username, c.ClientIP()); err != nil {
 		logrus.Warnf("telegram remind failed: %v", err)
-		if service.Telegram2FAEnabled(*loginDto.Username) {
-			c.JSON(http.StatusServiceUnavailable, gin.H{"code": "tg_unavailable"})
-			return
-		}
 		jwtVo.TelegramWarning = true
 	}
+	service.SaveLoginHistory(*loginDto.Username, c.ClientIP(), constant.LoginSuccess)
 	vo.Success(jwtVo, c)
 }
 
+func LoginStatus(c *gin.Context) {
+	loginStatusDto, err := validateField(c, dto.LoginStatusDto{})
+	if err != nil {
+		return
+	}
+	status, token, err := service.WaitLoginApproval(*loginStatusDto.PendingId, 25*time.Second)
+	if err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	loginStatusVo := vo.LoginStatusVo{
+		Status: status,
+	}
+	if token != "" {
+		loginStatusVo.TokenType = constant.TokenType
+		loginStatusVo.AccessToken = token
+	}
+	vo.Success(loginStatusVo, c)
+}
+
+func PageLoginHistory(c *gin.Context) {
+	loginHistoryPageDto, err := validateField(c, dto.LoginHistoryPageDto{})
+	if err != nil {
+		return
+	}
+	loginHistories, total, err := service.PageLoginHistory(loginHistoryPageDto)
+	if err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	var loginHistoryVos []vo.LoginHistoryVo
+	for _, item := range loginHistories {
+		loginHistoryVos = append(loginHistoryVos, vo.LoginHistoryVo{
+			Id:       *item.Id,
+			Username: *item.Username,
+			Ip:       *item.Ip,
+			Status:   *item.Status,
+			LoginAt:  *item.LoginAt,
+		})
+	}
+	vo.Success(vo.LoginHistoryPageVo{
+		LoginHistoryVos: loginHistoryVos,
+		Total:           total,
+	}, c)
+}
+
 func PageAccount(c *gin.Context) {
 	accountPageDto, err := validateField(c, dto.AccountPageDto{})
 	if err != nil {
@@ -339,3 +397,114 @@ func VerifyDefaultPass(c *gin.Context) {
 	}
 	vo.Success(account.Pass != nil && *account.Pass == "02f382b76ca1ab7aa06ab03345c7712fd5b971fb0c0f2aef98bac9cd", c)
 }
+
+func TelegramBindCode(c *gin.Context) {
+	idDto, err := validateField(c, dto.IdDto{})
+	if err != nil {
+		return
+	}
+	code, expireAt, err := service.TelegramBindCode(*idDto.Id)
+	if err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	vo.Success(vo.TelegramBindCodeVo{
+		Code:     code,
+		Link:     service.TelegramBindLink(code),
+		ExpireAt: expireAt,
+	}, c)
+}
+
+func TelegramUnbind(c *gin.Context) {
+	idDto, err := validateField(c, dto.IdDto{})
+	if err != nil {
+		return
+	}
+	if err = service.TelegramUnbind(*idDto.Id); err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	vo.Success(nil, c)
+}
+
+func PageSubscribeLog(c *gin.Context) {
+	subscribeLogPageDto, err := validateField(c, dto.SubscribeLogPageDto{})
+	if err != nil {
+		return
+	}
+	subscribeLogs, total, err := service.PageSubscribeLog(subscribeLogPageDto)
+	if err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	var subscribeLogVos []vo.SubscribeLogVo
+	for _, item := range subscribeLogs {
+		subscribeLogVos = append(subscribeLogVos, vo.SubscribeLogVo{
+			Id:        *item.Id,
+			AccountId: *item.AccountId,
+			Source:    *item.Source,
+			Ip:        *item.Ip,
+			UserAgent: *item.UserAgent,
+			Format:    *item.Format,
+			AccessAt:  *item.AccessAt,
+		})
+	}
+	vo.Success(vo.SubscribeLogPageVo{
+		SubscribeLogVos: subscribeLogVos,
+		Total:           total,
+	}, c)
+}
+
+func SubscribeLogStat(c *gin.Context) {
+	subscribeLogStatDto, err := validateField(c, dto.SubscribeLogStatDto{})
+	if err != nil {
+		return
+	}
+	var days int64 = 7
+	if subscribeLogStatDto.Days != nil {
+		days = *subscribeLogStatDto.Days
+	}
+	ips, userAgents, err := service.StatSubscribeLog(*subscribeLogStatDto.AccountId, days)
+	if err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	subscribeLogStatVo := vo.SubscribeLogStatVo{
+		Ips:        []vo.SubscribeLogStatItemVo{},
+		UserAgents: []vo.SubscribeLogStatItemVo{},
+	}
+	for _, item := range ips {
+		subscribeLogStatVo.Ips = append(subscribeLogStatVo.Ips, vo.SubscribeLogStatItemVo(item))
+	}
+	for _, item := range userAgents {
+		subscribeLogStatVo.UserAgents = append(subscribeLogStatVo.UserAgents, vo.SubscribeLogStatItemVo(item))
+	}
+	vo.Success(subscribeLogStatVo, c)
+}
+
+func PageShareFlag(c *gin.Context) {
+	shareFlagPageDto, err := validateField(c, dto.ShareFlagPageDto{})
+	if err != nil {
+		return
+	}
+	shareFlags, total, err := service.PageShareFlag(shareFlagPageDto)
+	if err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	var shareFlagVos []vo.ShareFlagVo
+	for _, item := range shareFlags {
+		shareFlagVos = append(shareFlagVos, vo.ShareFlagVo{
+			Id:        *item.Id,
+			AccountId: *item.AccountId,
+			IpCount:   *item.IpCount,
+			Ips:       strings.Split(*item.Ips, ","),
+			Action:    *item.Action,
+			FlagAt:    *item.FlagAt,
+		})
+	}
+	vo.Success(vo.ShareFlagPageVo{
+		ShareFlagVos: shareFlagVos,
+		Total:        total,
+	}, c)
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"h-ui/model/dto"
+	"h-ui/model/entity"
+	"h-ui/model/vo"
+	"h-ui/service"
+)
+
+func ListAlertRule(c *gin.Context) {
+	alertRules, err := service.ListAlertRule()
+	if err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	alertRuleVos := make([]vo.AlertRuleVo, 0)
+	for _, item := range alertRules {
+		alertRuleVos = append(alertRuleVos, vo.AlertRuleVo{
+			BaseVo: vo.BaseVo{
+				Id:         *item.Id,
+				CreateTime: *item.CreateTime,
+			},
+			Name:      *item.Name,
+			Type:      *item.Type,
+			Threshold: *item.Threshold,
+			Duration:  *item.Duration,
+			Cooldown:  *item.Cooldown,
+			Enable:    *item.Enable,
+			State:     service.AlertRuleState(*item.Id),
+		})
+	}
+	vo.Success(alertRuleVos, c)
+}
+
+func SaveAlertRule(c *gin.Context) {
+	alertRuleSaveDto, err := validateField(c, dto.AlertRuleSaveDto{})
+	if err != nil {
+		return
+	}
+	alertRule := entity.AlertRule{
+		Name:      alertRuleSaveDto.Name,
+		Type:      alertRuleSaveDto.Type,
+		Threshold: alertRuleSaveDto.Threshold,
+		Duration:  alertRuleSaveDto.Duration,
+		Cooldown:  alertRuleSaveDto.Cooldown,
+		Enable:    alertRuleSaveDto.Enable,
+	}
+	if err = service.SaveAlertRule(alertRule); err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	vo.Success(nil, c)
+}
+
+func UpdateAlertRule(c *gin.Context) {
+	alertRuleUpdateDto, err := validateField(c, dto.AlertRuleUpdateDto{})
+	if err != nil {
+		return
+	}
+	alertRule := entity.AlertRule{
+		Name:      alertRuleUpdateDto.Name,
+		Type:      alertRuleUpdateDto.Type,
+		Threshold: alertRuleUpdateDto.Threshold,
+		Duration:  alertRuleUpdateDto.Duration,
+		Cooldown:  alertRuleUpdateDto.Cooldown,
+		Enable:    alertRuleUpdateDto.Enable,
+		BaseEntity: entity.BaseEntity{
+			Id: alertRuleUpdateDto.Id,
+		},
+	}
+	if err = service.UpdateAlertRule(alertRule); err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	vo.Success(nil, c)
+}
+
+func DeleteAlertRule(c *gin.Context) {
+	idDto, err := validateField(c, dto.IdDto{})
+	if err != nil {
+		return
+	}
+	if err = service.DeleteAlertRule([]int64{*idDto.Id}); err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	vo.Success(nil, c)
+}
+
+func PageAlertHistory(c *gin.Context) {
+	alertHistoryPageDto, err := validateField(c, dto.AlertHistoryPageDto{})
+	if err != nil {
+		return
+	}
+	alertHistories, total, err := service.PageAlertHistory(alertHistoryPageDto)
+	if err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	alertHistoryVos := make([]vo.AlertHistoryVo, 0)
+	for _, item := range alertHistories {
+		alertHistoryVos = append(alertHistoryVos, vo.AlertHistoryVo{
+			Id:       *item.Id,
+			RuleId:   *item.RuleId,
+			RuleName: *item.RuleName,
+			Type:     *item.Type,
+			State:    *item.State,
+			Value:    *item.Value,
+			Message:  *item.Message,
+			Notified: *item.Notified,
+			AlertAt:  *item.AlertAt,
+		})
+	}
+	vo.Success(vo.AlertHistoryPageVo{
+		AlertHistoryVos: alertHistoryVos,
+		Total:           total,
+	}, c)
+}
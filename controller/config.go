@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/robfig/cron/v3"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
 	"h-ui/dao"
@@ -15,6 +16,8 @@ import (
 	"h-ui/service"
 	"h-ui/util"
 	"io"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -37,6 +40,17 @@ func UpdateConfigs(c *gin.Context) {
 
 	needResetPortHopping := false
 	needRestart := false
+	needReloadCert := false
+
+	// 保存前检查新的名单不会拦截当前管理员
+	updates := map[string]string{}
+	for _, item := range configsUpdateDto.ConfigUpdateDtos {
+		updates[*item.Key] = *item.Value
+	}
+	if err := service.ValidIPFilterClient(updates, c.ClientIP()); err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
 
 	for _, item := range configsUpdateDto.ConfigUpdateDtos {
 		key := *item.Key
@@ -59,14 +73,28 @@ func UpdateConfigs(c *gin.Context) {
 				vo.Fail(fmt.Sprintf("crt path: %s is not exist", value), c)
 				return
 			}
-			needRestart = true
+			needReloadCert = true
 		}
 		if key == constant.HUIKeyPath && keyPath != value {
 			if value != "" && !util.Exists(value) {
 				vo.Fail(fmt.Sprintf("key path: %s is not exist", value), c)
 				return
 			}
-			needRestart = true
+			needReloadCert = true
+		}
+
+		if key == constant.HUICertFromHysteria2 {
+			if value != "0" && value != "1" {
+				vo.Fail(fmt.Sprintf("%s: %s is invalid", key, value), c)
+				return
+			}
+			if value == "1" {
+				if _, err := service.Hysteria2AcmePath(); err != nil {
+					vo.Fail(fmt.Sprintf("%s: hysteria2 cert is unavailable: %v", key, err), c)
+					return
+				}
+			}
+			needReloadCert = true
 		}
 
 		if key == constant.HUIWebContext {
@@ -96,24 +124,183 @@ func UpdateConfigs(c *gin.Context) {
 			}
 		}
 
-		if key == constant.ResetTrafficCron {
-			resetTrafficCron, err := service.GetConfig(constant.ResetTrafficCron)
+		if key == constant.ResetTrafficCron ||
+			key == constant.TelegramReportDailyCron ||
+			key == constant.TelegramReportWeeklyCron ||
+			key == constant.TelegramReportMonthlyCron {
+			if value != "" {
+				if _, err := cron.ParseStandard(value); err != nil {
+					vo.Fail(fmt.Sprintf("cron: %s is invalid", value), c)
+					return
+				}
+			}
+			cronConfig, err := service.GetConfig(key)
 			if err != nil {
 				vo.Fail(err.Error(), c)
 				return
 			}
-			if *resetTrafficCron.Value != value {
+			if *cronConfig.Value != value {
 				needRestart = true
 			}
 		}
 
-		if key == constant.TelegramEnable {
-			telegramEnable, err := service.GetConfig(constant.TelegramEnable)
+		if key == constant.ClashExtension {
+			if err := service.ValidClashTemplate(value); err != nil {
+				vo.Fail(err.Error(), c)
+				return
+			}
+		}
+
+		if key == constant.HUITrustedProxies {
+			if err := service.ValidTrustedProxies(value); err != nil {
+				vo.Fail(err.Error(), c)
+				return
+			}
+		}
+
+		if key == constant.HUIBindAddress {
+			if err := service.ValidBindAddress(value); err != nil {
+				vo.Fail(err.Error(), c)
+				return
+			}
+		}
+
+		if key == constant.HUIUnixSocket && value != "" && !filepath.IsAbs(value) {
+			vo.Fail(fmt.Sprintf("unix socket: %s must be an absolute path", value), c)
+			return
+		}
+
+		if key == constant.HUIUnixSocketMode {
+			if _, err := service.ParseUnixSocketMode(value); err != nil {
+				vo.Fail(err.Error(), c)
+				return
+			}
+		}
+
+		if key == constant.HUIRealIpHeader && value != "" && !regexp.MustCompile("^[A-Za-z0-9-]+$").MatchString(value) {
+			vo.Fail(fmt.Sprintf("real ip header: %s is invalid", value), c)
+			return
+		}
+
+		if key == constant.AdminAllowIps || key == constant.AdminDenyIps ||
+			key == constant.PublicAllowIps || key == constant.PublicDenyIps {
+			if err := service.ValidIPFilterIps(key, value); err != nil {
+				vo.Fail(err.Error(), c)
+				return
+			}
+		}
+
+		if key == constant.AdminAllowCountries || key == constant.AdminDenyCountries ||
+			key == constant.PublicAllowCountries || key == constant.PublicDenyCountries {
+			if err := service.ValidIPFilterCountries(key, value); err != nil {
+				vo.Fail(err.Error(), c)
+				return
+			}
+		}
+
+		if key == constant.GeoIPDBPath {
+			if err := service.ValidGeoIPDB(value); err != nil {
+				vo.Fail(err.Error(), c)
+				return
+			}
+		}
+
+		// 监听地址和客户端 IP 的设置在面板启动时生效
+		if key == constant.HUITrustedProxies || key == constant.HUIBindAddress ||
+			key == constant.HUIUnixSocket || key == constant.HUIUnixSocketMode || key == constant.HUIRealIpHeader {
+			listenConfig, err := service.GetConfig(key)
 			if err != nil {
 				vo.Fail(err.Error(), c)
 				return
 			}
-			if *telegramEnable.Value != value {
+			if *listenConfig.Value != value {
+				needRestart = true
+			}
+		}
+
+		if key == constant.HUIPublicUrl && value != "" {
+			if publicUrl, err := url.Parse(value); err != nil || (publicUrl.Scheme != "http" && publicUrl.Scheme != "https") || publicUrl.Host == "" {
+				vo.Fail(fmt.Sprintf("public url: %s is invalid", value), c)
+				return
+			}
+		}
+
+		if key == constant.SubscribePort && value != "" {
+			if err := util.VerifyPort(value); err != nil {
+				vo.Fail(fmt.Sprintf("subscription port: %s is invalid", value), c)
+				return
+			}
+		}
+
+		if (key == constant.Hysteria2ClientSocks5 || key == constant.Hysteria2ClientHttp) && value != "" {
+			if _, port, err := net.SplitHostPort(value); err != nil || port == "" || util.VerifyPort(port) != nil {
+				vo.Fail(fmt.Sprintf("client listen: %s is invalid", value), c)
+				return
+			}
+		}
+
+		if key == constant.SubscribeLogDays ||
+			key == constant.CertExpireWarnDays ||
+			key == constant.ShareDetectMaxIps ||
+			key == constant.ShareDetectWindow ||
+			key == constant.ShareDetectKickMinutes {
+			if number, err := strconv.ParseInt(value, 10, 64); err != nil || number < 0 {
+				vo.Fail(fmt.Sprintf("%s: %s is invalid", key, value), c)
+				return
+			}
+		}
+
+		if key == constant.ShareDetectNetwork &&
+			value != constant.ShareNetworkIp && value != constant.ShareNetworkNetwork {
+			vo.Fail(fmt.Sprintf("sharing detection network: %s is invalid", value), c)
+			return
+		}
+
+		if key == constant.ShareDetectAction && !util.ArrContain([]string{
+			constant.ShareActionNone,
+			constant.ShareActionNotify,
+			constant.ShareActionRotate,
+			constant.ShareActionKick}, value) {
+			vo.Fail(fmt.Sprintf("sharing detection action: %s is invalid", value), c)
+			return
+		}
+
+		if key == constant.SubscribeUaRules {
+			if err := service.ValidSubscribeUaRules(value); err != nil {
+				vo.Fail(err.Error(), c)
+				return
+			}
+		}
+
+		if key == constant.SubscribeFormatDisabled {
+			if err := service.ValidSubscribeFormats(value); err != nil {
+				vo.Fail(err.Error(), c)
+				return
+			}
+		}
+
+		if key == constant.TelegramProxy {
+			if err := service.ValidTelegramProxy(value); err != nil {
+				vo.Fail(err.Error(), c)
+				return
+			}
+		}
+
+		if key == constant.TelegramApiEndpoint && value != "" &&
+			!strings.HasPrefix(value, "http://") && !strings.HasPrefix(value, "https://") {
+			vo.Fail(fmt.Sprintf("telegram api endpoint: %s is invalid", value), c)
+			return
+		}
+
+		if key == constant.TelegramEnable ||
+			key == constant.TelegramProxy ||
+			key == constant.TelegramApiEndpoint {
+			telegramConfig, err := service.GetConfig(key)
+			if err != nil {
+				vo.Fail(err.Error(), c)
+				return
+			}
+			if *telegramConfig.Value != value {
 				needRestart = true
 			}
 		}
@@ -131,6 +318,16 @@ func UpdateConfigs(c *gin.Context) {
 		}
 	}
 
+	// 证书路径变化时热加载，启用或关闭 TLS 时才需要重启
+	if needReloadCert && !needRestart {
+		restart, err := service.ReloadServerCert()
+		if err != nil {
+			vo.Fail(err.Error(), c)
+			return
+		}
+		needRestart = restart
+	}
+
 	if needRestart {
 		go func() {
 			_ = service.StopServer()
@@ -512,3 +709,70 @@ func UploadCertFile(c *gin.Context) {
 	}
 	vo.Success(certPath, c)
 }
+
+func ListCertInfo(c *gin.Context) {
+	certInfoVos, err := service.ListCertInfo()
+	if err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	vo.Success(certInfoVos, c)
+}
+
+func GenerateManagedCert(c *gin.Context) {
+	managedCertGenerateDto, err := validateField(c, dto.ManagedCertGenerateDto{})
+	if err != nil {
+		return
+	}
+	var days int64 = 3650
+	if managedCertGenerateDto.Days != nil {
+		days = *managedCertGenerateDto.Days
+	}
+	managedCertVo, err := service.GenerateManagedCert(*managedCertGenerateDto.Target,
+		strings.Split(*managedCertGenerateDto.Hosts, ","),
+		days,
+		managedCertGenerateDto.Ca != nil && *managedCertGenerateDto.Ca == 1)
+	if err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	if err = reloadManagedCert(managedCertVo.Target); err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	vo.Success(managedCertVo, c)
+}
+
+func RotateManagedCert(c *gin.Context) {
+	managedCertRotateDto, err := validateField(c, dto.ManagedCertRotateDto{})
+	if err != nil {
+		return
+	}
+	managedCertVo, err := service.RotateManagedCert(*managedCertRotateDto.Target)
+	if err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	if err = reloadManagedCert(managedCertVo.Target); err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	vo.Success(managedCertVo, c)
+}
+
+// reloadManagedCert 面板证书热加载，面板原来没有使用 TLS 时需要重启
+func reloadManagedCert(target string) error {
+	if target != constant.CertPanelName {
+		return nil
+	}
+	needRestart, err := service.ReloadServerCert()
+	if err != nil {
+		return err
+	}
+	if needRestart {
+		go func() {
+			_ = service.StopServer()
+		}()
+	}
+	return nil
+}
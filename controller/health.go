@@ -0,0 +1,21 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"h-ui/model/vo"
+	"h-ui/service"
+	"net/http"
+)
+
+func Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": vo.HealthOk})
+}
+
+func Readyz(c *gin.Context) {
+	readinessVo := service.Readiness()
+	status := http.StatusOK
+	if readinessVo.Status != vo.HealthOk {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, readinessVo)
+}
@@ -11,6 +11,7 @@ import (
 	"h-ui/model/vo"
 	"h-ui/service"
 	"h-ui/util"
+	"net/http"
 	"net/url"
 	"os"
 	"strings"
@@ -37,6 +38,7 @@ func Hysteria2Auth(c *gin.Context) {
 		vo.Fail(err.Error(), c)
 		return
 	}
+	go service.SaveAuthLog(id, *hysteria2AuthDto.Addr)
 	vo.Hysteria2AuthSuccess(username, c)
 }
 
@@ -162,40 +164,105 @@ func Hysteria2Subscribe(c *gin.Context) {
 		vo.Fail("url decode err", c)
 		return
 	}
-	userAgent := strings.ToLower(c.Request.Header.Get("User-Agent"))
-	host := c.Request.Host
-
+	protocol, host := requestOrigin(c)
 	if host == "" {
 		vo.Fail("Host is empty", c)
 		return
 	}
 
-	var clientType string
-	if strings.Contains(userAgent, constant.Shadowrocket) {
-		clientType = constant.Shadowrocket
-	} else if strings.Contains(userAgent, constant.Clash) {
-		clientType = constant.Clash
-	} else if strings.Contains(userAgent, constant.V2rayN) {
-		clientType = constant.V2rayN
-	} else if strings.Contains(userAgent, constant.NekoBox) {
-		clientType = constant.NekoBox
-	} else {
-		clientType = constant.Clash
+	format := c.Query("format")
+	if format == "" {
+		format = c.Query("target")
+	}
+	clientType, err := service.SubscribeClientType(format, c.Request.UserAgent())
+	if err != nil {
+		vo.Fail(err.Error(), c)
+		return
 	}
 
-	userInfo, configStr, err := service.Hysteria2Subscribe(conPass, clientType, host)
+	userInfo, configStr, err := service.Hysteria2Subscribe(conPass, clientType, protocol, host)
 	if err != nil {
 		vo.Fail(err.Error(), c)
 		return
 	}
 
-	if clientType == constant.Shadowrocket || clientType == constant.Clash {
+	if clientType == constant.Shadowrocket || clientType == constant.Clash || clientType == constant.Stash {
 		c.Header("content-disposition", "attachment; filename=hui.yaml")
 		c.Header("profile-update-interval", "12")
 		c.Header("subscription-userinfo", userInfo)
+	} else if clientType == constant.NekoBox || clientType == constant.SingBox {
+		c.Header("content-disposition", "attachment; filename=hui.json")
+		c.Header("profile-update-interval", "12")
+		c.Header("subscription-userinfo", userInfo)
+	} else if clientType == constant.Surge {
+		c.Header("content-disposition", "attachment; filename=hui.conf")
+		c.Header("subscription-userinfo", userInfo)
+	} else if clientType == constant.Loon || clientType == constant.QuantumultX {
+		c.Header("subscription-userinfo", userInfo)
+	} else if clientType == constant.Hysteria2 {
+		c.Header("content-disposition", "attachment; filename=hui-client.yaml")
+		c.Header("subscription-userinfo", userInfo)
 	} else if clientType == constant.V2rayN {
 		configStr = base64.StdEncoding.EncodeToString([]byte(configStr))
 	}
 
+	go service.SaveSubscribeLog(conPass, c.ClientIP(), c.Request.UserAgent(), clientType)
 	c.String(200, configStr)
 }
+
+func Hysteria2ClientConfig(c *gin.Context) {
+	hysteria2ClientConfigDto, err := validateField(c, dto.Hysteria2ClientConfigDto{})
+	if err != nil {
+		return
+	}
+	configStr, err := service.Hysteria2ClientConfig(*hysteria2ClientConfigDto.AccountId,
+		*hysteria2ClientConfigDto.Hostname,
+		hysteria2ClientConfigDto.NodeId,
+		hysteria2ClientConfigDto.Socks5,
+		hysteria2ClientConfigDto.Http)
+	if err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename=hui-client.yaml")
+	c.Data(http.StatusOK, "application/octet-stream", []byte(configStr))
+}
+
+func ClashPreview(c *gin.Context) {
+	clashPreviewDto, err := validateField(c, dto.ClashPreviewDto{})
+	if err != nil {
+		return
+	}
+	clientType := constant.Clash
+	if clashPreviewDto.Format != nil && *clashPreviewDto.Format != "" {
+		clientType = *clashPreviewDto.Format
+	}
+	configStr, err := service.ClashPreview(*clashPreviewDto.AccountId,
+		clientType,
+		clashPreviewDto.Template,
+		*clashPreviewDto.Protocol,
+		*clashPreviewDto.Host)
+	if err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	vo.Success(configStr, c)
+}
+
+// requestOrigin 客户端访问面板使用的协议和 Host，可信代理或 unix socket 转发时使用 X-Forwarded-Proto 和 X-Forwarded-Host
+func requestOrigin(c *gin.Context) (string, string) {
+	protocol := "http:"
+	if c.Request.TLS != nil {
+		protocol = "https:"
+	}
+	host := c.Request.Host
+	if service.FromUnixSocket(c.Request) || service.TrustedProxy(c.RemoteIP()) {
+		if forwardedProto := strings.TrimSpace(strings.Split(c.GetHeader("X-Forwarded-Proto"), ",")[0]); forwardedProto == "http" || forwardedProto == "https" {
+			protocol = forwardedProto + ":"
+		}
+		if forwardedHost := strings.TrimSpace(strings.Split(c.GetHeader("X-Forwarded-Host"), ",")[0]); forwardedHost != "" {
+			host = forwardedHost
+		}
+	}
+	return protocol, host
+}
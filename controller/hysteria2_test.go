@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"h-ui/dao"
+	"h-ui/model/constant"
+)
+
+func TestHysteria2SubscribeUserInfo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/hui/:conPass", Hysteria2Subscribe)
+	config, err := dao.GetConfig("key = ?", constant.Hysteria2Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = dao.UpdateConfig([]string{constant.Hysteria2Config}, map[string]interface{}{"value": "listen: :443\n"}); err != nil {
+		t.Fatal(err)
+	}
+	defer dao.UpdateConfig([]string{constant.Hysteria2Config}, map[string]interface{}{"value": *config.Value})
+
+	// 除 v2rayN 外都返回流量和到期时间
+	cases := map[string]bool{
+		"Surge iOS/2920":              true,
+		"Loon/731 CFNetwork/1410.0.3": true,
+		"clash-verge/v1.3.8":          true,
+		"v2rayN/6.23":                 false,
+	}
+	for userAgent, expected := range cases {
+		req := httptest.NewRequest("GET", "/hui/sysadmin.sysadmin", nil)
+		req.Header.Set("User-Agent", userAgent)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status %d", userAgent, w.Code)
+		}
+		userInfo := w.Header().Get("subscription-userinfo")
+		if expected != strings.HasPrefix(userInfo, "upload=0; download=0; total=-1; expire=") {
+			t.Errorf("%s: unexpected subscription-userinfo %q", userAgent, userInfo)
+		}
+	}
+}
@@ -37,3 +37,36 @@ func TestLoginTelegramFailure(t *testing.T) {
 		t.Fatalf("missing warning")
 	}
 }
+
+func TestLoginTelegramApprovalPending(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/hui/auth/login", Login)
+	oldExist := service.ExistAccountUsername
+	oldLogin := service.Login
+	old2FA := service.Telegram2FAEnabled
+	oldApproval := service.RequestLoginApproval
+	service.ExistAccountUsername = func(string, int64) bool { return true }
+	service.Login = func(string, string) (string, error) { return "token", nil }
+	service.Telegram2FAEnabled = func(string) bool { return true }
+	service.RequestLoginApproval = func(string, string, string) (string, error) { return "pending123", nil }
+	defer func() {
+		service.ExistAccountUsername = oldExist
+		service.Login = oldLogin
+		service.Telegram2FAEnabled = old2FA
+		service.RequestLoginApproval = oldApproval
+	}()
+	req := httptest.NewRequest("POST", "/hui/auth/login", strings.NewReader(`{"username":"user123","pass":"abcdef"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "pending123") {
+		t.Fatalf("missing pending id")
+	}
+	if strings.Contains(w.Body.String(), `"accessToken":"token"`) {
+		t.Fatalf("token issued before approval")
+	}
+}
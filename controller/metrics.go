@@ -0,0 +1,20 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"h-ui/service"
+	"net/http"
+	"strings"
+)
+
+func Metrics(c *gin.Context) {
+	token := c.Query("token")
+	if authHeader := c.Request.Header.Get("Authorization"); authHeader != "" {
+		token = strings.TrimPrefix(authHeader, "Bearer ")
+	}
+	if !service.MetricsAllow(token, c.ClientIP()) {
+		c.String(http.StatusForbidden, "forbidden")
+		return
+	}
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(service.Metrics()))
+}
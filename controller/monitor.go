@@ -2,6 +2,7 @@ package controller
 
 import (
 	"github.com/gin-gonic/gin"
+	"h-ui/model/dto"
 	"h-ui/model/vo"
 	"h-ui/service"
 )
@@ -24,3 +25,16 @@ func MonitorHysteria2(c *gin.Context) {
 	vo.Success(hysteria2MonitorVo, c)
 	return
 }
+
+func ListSystemMetric(c *gin.Context) {
+	systemMetricDto, err := validateField(c, dto.SystemMetricDto{})
+	if err != nil {
+		return
+	}
+	systemMetricVos, err := service.ListSystemMetric(*systemMetricDto.Period)
+	if err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	vo.Success(systemMetricVos, c)
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"h-ui/model/dto"
+	"h-ui/model/entity"
+	"h-ui/model/vo"
+	"h-ui/service"
+)
+
+func ListNode(c *gin.Context) {
+	nodes, err := service.ListNode()
+	if err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	nodeVos := make([]vo.NodeVo, 0)
+	for _, item := range nodes {
+		nodeVos = append(nodeVos, vo.NodeVo{
+			BaseVo: vo.BaseVo{
+				Id:         *item.Id,
+				CreateTime: *item.CreateTime,
+			},
+			Name:         *item.Name,
+			Host:         *item.Host,
+			Port:         *item.Port,
+			PortHopping:  *item.PortHopping,
+			Sni:          *item.Sni,
+			ObfsPassword: *item.ObfsPassword,
+			Sort:         *item.Sort,
+			Enable:       *item.Enable,
+		})
+	}
+	vo.Success(nodeVos, c)
+}
+
+func SaveNode(c *gin.Context) {
+	nodeSaveDto, err := validateField(c, dto.NodeSaveDto{})
+	if err != nil {
+		return
+	}
+	portHopping, sni, obfsPassword := "", "", ""
+	var sort int64 = 0
+	if nodeSaveDto.PortHopping != nil {
+		portHopping = *nodeSaveDto.PortHopping
+	}
+	if nodeSaveDto.Sni != nil {
+		sni = *nodeSaveDto.Sni
+	}
+	if nodeSaveDto.ObfsPassword != nil {
+		obfsPassword = *nodeSaveDto.ObfsPassword
+	}
+	if nodeSaveDto.Sort != nil {
+		sort = *nodeSaveDto.Sort
+	}
+	node := entity.Node{
+		Name:         nodeSaveDto.Name,
+		Host:         nodeSaveDto.Host,
+		Port:         nodeSaveDto.Port,
+		PortHopping:  &portHopping,
+		Sni:          &sni,
+		ObfsPassword: &obfsPassword,
+		Sort:         &sort,
+		Enable:       nodeSaveDto.Enable,
+	}
+	if err = service.SaveNode(node); err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	vo.Success(nil, c)
+}
+
+func UpdateNode(c *gin.Context) {
+	nodeUpdateDto, err := validateField(c, dto.NodeUpdateDto{})
+	if err != nil {
+		return
+	}
+	node := entity.Node{
+		BaseEntity: entity.BaseEntity{
+			Id: nodeUpdateDto.Id,
+		},
+		Name:         nodeUpdateDto.Name,
+		Host:         nodeUpdateDto.Host,
+		Port:         nodeUpdateDto.Port,
+		PortHopping:  nodeUpdateDto.PortHopping,
+		Sni:          nodeUpdateDto.Sni,
+		ObfsPassword: nodeUpdateDto.ObfsPassword,
+		Sort:         nodeUpdateDto.Sort,
+		Enable:       nodeUpdateDto.Enable,
+	}
+	if err = service.UpdateNode(node); err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	vo.Success(nil, c)
+}
+
+func DeleteNode(c *gin.Context) {
+	idDto, err := validateField(c, dto.IdDto{})
+	if err != nil {
+		return
+	}
+	if err = service.DeleteNode([]int64{*idDto.Id}); err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	vo.Success(nil, c)
+}
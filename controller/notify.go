@@ -0,0 +1,20 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"h-ui/model/dto"
+	"h-ui/model/vo"
+	"h-ui/service"
+)
+
+func NotifyTest(c *gin.Context) {
+	notifyTestDto, err := validateField(c, dto.NotifyTestDto{})
+	if err != nil {
+		return
+	}
+	if err = service.NotifyTest(*notifyTestDto.Channel); err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	vo.Success(nil, c)
+}
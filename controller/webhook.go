@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"h-ui/model/dto"
+	"h-ui/model/entity"
+	"h-ui/model/vo"
+	"h-ui/service"
+)
+
+func ListWebhook(c *gin.Context) {
+	webhooks, err := service.ListWebhook()
+	if err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	webhookVos := make([]vo.WebhookVo, 0)
+	for _, item := range webhooks {
+		webhookVos = append(webhookVos, vo.WebhookVo{
+			BaseVo: vo.BaseVo{
+				Id:         *item.Id,
+				CreateTime: *item.CreateTime,
+			},
+			Name:      *item.Name,
+			Url:       *item.Url,
+			HasSecret: *item.Secret != "",
+			Events:    *item.Events,
+			Enable:    *item.Enable,
+		})
+	}
+	vo.Success(webhookVos, c)
+}
+
+func SaveWebhook(c *gin.Context) {
+	webhookSaveDto, err := validateField(c, dto.WebhookSaveDto{})
+	if err != nil {
+		return
+	}
+	secret, events := "", ""
+	if webhookSaveDto.Secret != nil {
+		secret = *webhookSaveDto.Secret
+	}
+	if webhookSaveDto.Events != nil {
+		events = *webhookSaveDto.Events
+	}
+	webhook := entity.Webhook{
+		Name:   webhookSaveDto.Name,
+		Url:    webhookSaveDto.Url,
+		Secret: &secret,
+		Events: &events,
+		Enable: webhookSaveDto.Enable,
+	}
+	if err = service.SaveWebhook(webhook); err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	vo.Success(nil, c)
+}
+
+func UpdateWebhook(c *gin.Context) {
+	webhookUpdateDto, err := validateField(c, dto.WebhookUpdateDto{})
+	if err != nil {
+		return
+	}
+	webhook := entity.Webhook{
+		BaseEntity: entity.BaseEntity{
+			Id: webhookUpdateDto.Id,
+		},
+		Name:   webhookUpdateDto.Name,
+		Url:    webhookUpdateDto.Url,
+		Secret: webhookUpdateDto.Secret,
+		Events: webhookUpdateDto.Events,
+		Enable: webhookUpdateDto.Enable,
+	}
+	if err = service.UpdateWebhook(webhook); err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	vo.Success(nil, c)
+}
+
+func DeleteWebhook(c *gin.Context) {
+	idDto, err := validateField(c, dto.IdDto{})
+	if err != nil {
+		return
+	}
+	if err = service.DeleteWebhook([]int64{*idDto.Id}); err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	vo.Success(nil, c)
+}
+
+func PageWebhookDelivery(c *gin.Context) {
+	webhookDeliveryPageDto, err := validateField(c, dto.WebhookDeliveryPageDto{})
+	if err != nil {
+		return
+	}
+	deliveries, total, err := service.PageWebhookDelivery(webhookDeliveryPageDto)
+	if err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	var webhookDeliveryVos []vo.WebhookDeliveryVo
+	for _, item := range deliveries {
+		webhookDeliveryVos = append(webhookDeliveryVos, vo.WebhookDeliveryVo{
+			Id:           *item.Id,
+			WebhookId:    *item.WebhookId,
+			Event:        *item.Event,
+			Payload:      *item.Payload,
+			Status:       *item.Status,
+			Attempts:     *item.Attempts,
+			ResponseCode: *item.ResponseCode,
+			Error:        *item.Error,
+			NextRetryAt:  *item.NextRetryAt,
+			DeliveredAt:  *item.DeliveredAt,
+			EventAt:      *item.EventAt,
+		})
+	}
+	vo.Success(vo.WebhookDeliveryPageVo{
+		WebhookDeliveryVos: webhookDeliveryVos,
+		Total:              total,
+	}, c)
+}
+
+func ReplayWebhookDelivery(c *gin.Context) {
+	idDto, err := validateField(c, dto.IdDto{})
+	if err != nil {
+		return
+	}
+	if err = service.ReplayWebhookDelivery(*idDto.Id); err != nil {
+		vo.Fail(err.Error(), c)
+		return
+	}
+	vo.Success(nil, c)
+}
@@ -41,6 +41,18 @@ func UpdateAccount(ids []int64, updates map[string]interface{}) error {
 	return nil
 }
 
+// UpdateAccountFirstConAt 只在 con_at 为 0 时更新，返回是否为首次连接
+func UpdateAccountFirstConAt(id int64, conAt int64) (bool, error) {
+	tx := sqliteDB.Model(&entity.Account{}).
+		Where("id = ? and con_at = 0", id).
+		Updates(map[string]interface{}{"con_at": conAt, "update_time": time.Now().Format("2006-01-02 15:04:05")})
+	if tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return false, errors.New(constant.SysError)
+	}
+	return tx.RowsAffected > 0, nil
+}
+
 func UpsertAccount(accounts []entity.Account) error {
 	if tx := sqliteDB.Model(&entity.Account{}).Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "username"}},
@@ -0,0 +1,97 @@
+package dao
+
+import (
+	"errors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"h-ui/model/constant"
+	"h-ui/model/dto"
+	"h-ui/model/entity"
+	"time"
+)
+
+func SaveAlertRule(alertRule entity.AlertRule) (int64, error) {
+	if tx := sqliteDB.Save(&alertRule); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return 0, errors.New(constant.SysError)
+	}
+	return *alertRule.Id, nil
+}
+
+func UpdateAlertRule(ids []int64, updates map[string]interface{}) error {
+	if len(updates) > 0 {
+		updates["update_time"] = time.Now().Format("2006-01-02 15:04:05")
+		if tx := sqliteDB.Model(&entity.AlertRule{}).
+			Where("id in ?", ids).
+			Updates(updates); tx.Error != nil {
+			logrus.Errorf("%v", tx.Error)
+			return errors.New(constant.SysError)
+		}
+	}
+	return nil
+}
+
+func DeleteAlertRule(ids []int64) error {
+	if tx := sqliteDB.Where("id in ?", ids).Delete(&entity.AlertRule{}); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return errors.New(constant.SysError)
+	}
+	return nil
+}
+
+func GetAlertRule(query interface{}, args ...interface{}) (entity.AlertRule, error) {
+	var alertRule entity.AlertRule
+	if tx := sqliteDB.Model(&entity.AlertRule{}).
+		Where(query, args...).First(&alertRule); tx.Error != nil {
+		if tx.Error == gorm.ErrRecordNotFound {
+			return alertRule, errors.New("alert rule not exist")
+		}
+		logrus.Errorf("%v", tx.Error)
+		return alertRule, errors.New(constant.SysError)
+	}
+	return alertRule, nil
+}
+
+func ListAlertRule(query interface{}, args ...interface{}) ([]entity.AlertRule, error) {
+	var alertRules []entity.AlertRule
+	if tx := sqliteDB.Model(&entity.AlertRule{}).
+		Where(query, args...).Order("id").Find(&alertRules); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return alertRules, errors.New(constant.SysError)
+	}
+	return alertRules, nil
+}
+
+func SaveAlertHistory(alertHistory entity.AlertHistory) (int64, error) {
+	if tx := sqliteDB.Save(&alertHistory); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return 0, errors.New(constant.SysError)
+	}
+	return *alertHistory.Id, nil
+}
+
+func PageAlertHistory(alertHistoryPageDto dto.AlertHistoryPageDto) ([]entity.AlertHistory, int64, error) {
+	var alertHistories []entity.AlertHistory
+	var total int64
+	tx := sqliteDB.Model(&entity.AlertHistory{})
+	if alertHistoryPageDto.RuleId != nil {
+		tx.Where("rule_id = ?", *alertHistoryPageDto.RuleId)
+	}
+	if alertHistoryPageDto.State != nil && *alertHistoryPageDto.State != "" {
+		tx.Where("state = ?", *alertHistoryPageDto.State)
+	}
+	if alertHistoryPageDto.StartTime != nil {
+		tx.Where("alert_at >= ?", *alertHistoryPageDto.StartTime)
+	}
+	if alertHistoryPageDto.EndTime != nil {
+		tx.Where("alert_at <= ?", *alertHistoryPageDto.EndTime)
+	}
+	tx.Count(&total)
+	if tx.Scopes(Paginate(alertHistoryPageDto.PageNum, alertHistoryPageDto.PageSize)).
+		Order("alert_at desc").
+		Find(&alertHistories); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return alertHistories, 0, errors.New(constant.SysError)
+	}
+	return alertHistories, total, nil
+}
@@ -0,0 +1,44 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"h-ui/model/constant"
+	"h-ui/model/dto"
+	"h-ui/model/entity"
+)
+
+func SaveLoginHistory(loginHistory entity.LoginHistory) (int64, error) {
+	if tx := sqliteDB.Save(&loginHistory); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return 0, errors.New(constant.SysError)
+	}
+	return *loginHistory.Id, nil
+}
+
+func PageLoginHistory(loginHistoryPageDto dto.LoginHistoryPageDto) ([]entity.LoginHistory, int64, error) {
+	var loginHistories []entity.LoginHistory
+	var total int64
+	tx := sqliteDB.Model(&entity.LoginHistory{})
+	if loginHistoryPageDto.Username != nil && *loginHistoryPageDto.Username != "" {
+		tx.Where("username like ?", fmt.Sprintf("%%%s%%", *loginHistoryPageDto.Username))
+	}
+	if loginHistoryPageDto.Status != nil && *loginHistoryPageDto.Status != "" {
+		tx.Where("status = ?", *loginHistoryPageDto.Status)
+	}
+	if loginHistoryPageDto.StartTime != nil {
+		tx.Where("login_at >= ?", *loginHistoryPageDto.StartTime)
+	}
+	if loginHistoryPageDto.EndTime != nil {
+		tx.Where("login_at <= ?", *loginHistoryPageDto.EndTime)
+	}
+	tx.Count(&total)
+	if tx.Scopes(Paginate(loginHistoryPageDto.PageNum, loginHistoryPageDto.PageSize)).
+		Order("login_at desc").
+		Find(&loginHistories); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return loginHistories, 0, errors.New(constant.SysError)
+	}
+	return loginHistories, total, nil
+}
@@ -0,0 +1,62 @@
+package dao
+
+import (
+	"errors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"h-ui/model/constant"
+	"h-ui/model/entity"
+	"time"
+)
+
+func SaveNode(node entity.Node) (int64, error) {
+	if tx := sqliteDB.Save(&node); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return 0, errors.New(constant.SysError)
+	}
+	return *node.Id, nil
+}
+
+func UpdateNode(ids []int64, updates map[string]interface{}) error {
+	if len(updates) > 0 {
+		updates["update_time"] = time.Now().Format("2006-01-02 15:04:05")
+		if tx := sqliteDB.Model(&entity.Node{}).
+			Where("id in ?", ids).
+			Updates(updates); tx.Error != nil {
+			logrus.Errorf("%v", tx.Error)
+			return errors.New(constant.SysError)
+		}
+	}
+	return nil
+}
+
+func DeleteNode(ids []int64) error {
+	if tx := sqliteDB.Where("id in ?", ids).Delete(&entity.Node{}); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return errors.New(constant.SysError)
+	}
+	return nil
+}
+
+func GetNode(query interface{}, args ...interface{}) (entity.Node, error) {
+	var node entity.Node
+	if tx := sqliteDB.Model(&entity.Node{}).
+		Where(query, args...).First(&node); tx.Error != nil {
+		if tx.Error == gorm.ErrRecordNotFound {
+			return node, errors.New("node not exist")
+		}
+		logrus.Errorf("%v", tx.Error)
+		return node, errors.New(constant.SysError)
+	}
+	return node, nil
+}
+
+func ListNode(query interface{}, args ...interface{}) ([]entity.Node, error) {
+	var nodes []entity.Node
+	if tx := sqliteDB.Model(&entity.Node{}).
+		Where(query, args...).Order("sort").Order("id").Find(&nodes); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return nodes, errors.New(constant.SysError)
+	}
+	return nodes, nil
+}
@@ -0,0 +1,30 @@
+package dao
+
+import (
+	"errors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"h-ui/model/constant"
+	"h-ui/model/entity"
+)
+
+func GetReportSnapshot(period string) (entity.ReportSnapshot, error) {
+	var reportSnapshot entity.ReportSnapshot
+	if tx := sqliteDB.Model(&entity.ReportSnapshot{}).
+		Where("period = ?", period).First(&reportSnapshot); tx.Error != nil {
+		if tx.Error == gorm.ErrRecordNotFound {
+			return reportSnapshot, errors.New("report snapshot not exist")
+		}
+		logrus.Errorf("%v", tx.Error)
+		return reportSnapshot, errors.New(constant.SysError)
+	}
+	return reportSnapshot, nil
+}
+
+func SaveReportSnapshot(reportSnapshot entity.ReportSnapshot) error {
+	if tx := sqliteDB.Save(&reportSnapshot); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return errors.New(constant.SysError)
+	}
+	return nil
+}
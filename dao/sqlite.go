@@ -15,7 +15,7 @@ import (
 	"time"
 )
 
-var sqlInitStr = "CREATE TABLE IF NOT EXISTS account\n(\n    id             INTEGER PRIMARY KEY AUTOINCREMENT,\n    username       TEXT    NOT NULL UNIQUE DEFAULT '',\n    pass           TEXT    NOT NULL        DEFAULT '',\n    con_pass       TEXT    NOT NULL        DEFAULT '',\n    quota          INTEGER NOT NULL        DEFAULT 0,\n    download       INTEGER NOT NULL        DEFAULT 0,\n    upload         INTEGER NOT NULL        DEFAULT 0,\n    expire_time    INTEGER NOT NULL        DEFAULT 0,\n    kick_util_time INTEGER NOT NULL        DEFAULT 0,\n    device_no      INTEGER NOT NULL        DEFAULT 3,\n    role           TEXT    NOT NULL        DEFAULT 'user',\n    deleted        INTEGER NOT NULL        DEFAULT 0,\n    create_time    TIMESTAMP               DEFAULT CURRENT_TIMESTAMP,\n    update_time    TIMESTAMP               DEFAULT CURRENT_TIMESTAMP\n);\nALTER TABLE account\n    ADD COLUMN login_at INTEGER NOT NULL DEFAULT 0;\nALTER TABLE account\n    ADD COLUMN con_at INTEGER NOT NULL DEFAULT 0;\nCREATE INDEX IF NOT EXISTS account_deleted_index ON account (deleted);\nCREATE INDEX IF NOT EXISTS account_username_index ON account (username);\nCREATE INDEX IF NOT EXISTS account_con_pass_index ON account (con_pass);\nCREATE INDEX IF NOT EXISTS account_pass_index ON account (pass);\nINSERT INTO account (id, username, pass, con_pass, quota, download, upload, expire_time, device_no, role)\nSELECT 1 ,'sysadmin', '02f382b76ca1ab7aa06ab03345c7712fd5b971fb0c0f2aef98bac9cd', 'sysadmin.sysadmin', -1, 0, 0, 253370736000000, 6, 'admin'\n    WHERE NOT EXISTS (SELECT 1 FROM account WHERE id = 1);\nCREATE TABLE IF NOT EXISTS config\n(\n    id          INTEGER PRIMARY KEY AUTOINCREMENT,\n    key         TEXT NOT NULL UNIQUE DEFAULT '',\n    value       TEXT NOT NULL        DEFAULT '',\n    remark      TEXT NOT NULL        DEFAULT '',\n    create_time TIMESTAMP            DEFAULT CURRENT_TIMESTAMP,\n    update_time TIMESTAMP            DEFAULT CURRENT_TIMESTAMP\n);\nCREATE INDEX IF NOT EXISTS config_key_index ON config (key);\nINSERT INTO config (key, value, remark)\nSELECT 'H_UI_WEB_PORT', '8081', 'H UI Web Port'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'H_UI_WEB_PORT');\nINSERT INTO config (key, value, remark)\nSELECT 'H_UI_WEB_CONTEXT', '/', 'H UI Web Context'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'H_UI_WEB_CONTEXT');\nINSERT INTO config (key, value, remark)\nSELECT 'H_UI_CRT_PATH', '', 'H UI Crt File Path'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'H_UI_CRT_PATH');\nINSERT INTO config (key, value, remark)\nSELECT 'H_UI_KEY_PATH', '', 'H UI Key File Path'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'H_UI_KEY_PATH');\nINSERT INTO config (key, value, remark)\nSELECT 'JWT_SECRET', hex(randomblob(10)), 'JWT Secret'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'JWT_SECRET');\nINSERT INTO config (key, value, remark)\nSELECT 'HYSTERIA2_ENABLE', '0', 'Hysteria2 Switch'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HYSTERIA2_ENABLE');\nINSERT INTO config (key, value, remark)\nSELECT 'HYSTERIA2_CONFIG', '', 'Hysteria2 Config'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HYSTERIA2_CONFIG');\nINSERT INTO config (key, value, remark)\nSELECT 'HYSTERIA2_TRAFFIC_TIME', '1', 'Hysteria2 Traffic Time'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HYSTERIA2_TRAFFIC_TIME');\nINSERT INTO config (key, value, remark)\nSELECT 'HYSTERIA2_CONFIG_REMARK', '', 'Hysteria2 Config Remark'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HYSTERIA2_CONFIG_REMARK');\nINSERT INTO config (key, value, remark)\nSELECT 'HYSTERIA2_CONFIG_PORT_HOPPING', '', 'Hysteria2 Config Port Hopping'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HYSTERIA2_CONFIG_PORT_HOPPING');\nINSERT INTO config (key, value, remark)\nSELECT 'RESET_TRAFFIC_CRON', '', 'Reset Traffic Cron'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'RESET_TRAFFIC_CRON');\nINSERT INTO config (key, value, remark)\nSELECT 'TELEGRAM_ENABLE', '0', 'Telegram Switch'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_ENABLE');\nINSERT INTO config (key, value, remark)\nSELECT 'TELEGRAM_TOKEN', '', 'Telegram Token'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_TOKEN');\nINSERT INTO config (key, value, remark)\nSELECT 'TELEGRAM_CHAT_ID', '', 'Telegram ChatId'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_CHAT_ID');\nINSERT INTO config (key, value, remark)\nSELECT 'TELEGRAM_LOGIN_JOB_ENABLE', '0', 'TELEGRAM LOGIN Notification'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_LOGIN_JOB_ENABLE');\nINSERT INTO config (key, value, remark)\nSELECT 'TELEGRAM_LOGIN_JOB_TEXT', '[time], [username] logged into the panel, IP address is [ip]', 'TELEGRAM LOGIN Notification Text'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_LOGIN_JOB_TEXT');\nINSERT INTO config (key, value, remark)\nSELECT 'CLASH_EXTENSION', '', 'Clash Subscription Extension'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'CLASH_EXTENSION');"
+var sqlInitStr = "CREATE TABLE IF NOT EXISTS account\n(\n    id             INTEGER PRIMARY KEY AUTOINCREMENT,\n    username       TEXT    NOT NULL UNIQUE DEFAULT '',\n    pass           TEXT    NOT NULL        DEFAULT '',\n    con_pass       TEXT    NOT NULL        DEFAULT '',\n    quota          INTEGER NOT NULL        DEFAULT 0,\n    download       INTEGER NOT NULL        DEFAULT 0,\n    upload         INTEGER NOT NULL        DEFAULT 0,\n    expire_time    INTEGER NOT NULL        DEFAULT 0,\n    kick_util_time INTEGER NOT NULL        DEFAULT 0,\n    device_no      INTEGER NOT NULL        DEFAULT 3,\n    role           TEXT    NOT NULL        DEFAULT 'user',\n    deleted        INTEGER NOT NULL        DEFAULT 0,\n    create_time    TIMESTAMP               DEFAULT CURRENT_TIMESTAMP,\n    update_time    TIMESTAMP               DEFAULT CURRENT_TIMESTAMP\n);\nALTER TABLE account\n    ADD COLUMN login_at INTEGER NOT NULL DEFAULT 0;\nALTER TABLE account\n    ADD COLUMN con_at INTEGER NOT NULL DEFAULT 0;\nCREATE INDEX IF NOT EXISTS account_deleted_index ON account (deleted);\nCREATE INDEX IF NOT EXISTS account_username_index ON account (username);\nCREATE INDEX IF NOT EXISTS account_con_pass_index ON account (con_pass);\nCREATE INDEX IF NOT EXISTS account_pass_index ON account (pass);\nINSERT INTO account (id, username, pass, con_pass, quota, download, upload, expire_time, device_no, role)\nSELECT 1 ,'sysadmin', '02f382b76ca1ab7aa06ab03345c7712fd5b971fb0c0f2aef98bac9cd', 'sysadmin.sysadmin', -1, 0, 0, 253370736000000, 6, 'admin'\n    WHERE NOT EXISTS (SELECT 1 FROM account WHERE id = 1);\nCREATE TABLE IF NOT EXISTS config\n(\n    id          INTEGER PRIMARY KEY AUTOINCREMENT,\n    key         TEXT NOT NULL UNIQUE DEFAULT '',\n    value       TEXT NOT NULL        DEFAULT '',\n    remark      TEXT NOT NULL        DEFAULT '',\n    create_time TIMESTAMP            DEFAULT CURRENT_TIMESTAMP,\n    update_time TIMESTAMP            DEFAULT CURRENT_TIMESTAMP\n);\nCREATE INDEX IF NOT EXISTS config_key_index ON config (key);\nINSERT INTO config (key, value, remark)\nSELECT 'H_UI_WEB_PORT', '8081', 'H UI Web Port'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'H_UI_WEB_PORT');\nINSERT INTO config (key, value, remark)\nSELECT 'H_UI_WEB_CONTEXT', '/', 'H UI Web Context'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'H_UI_WEB_CONTEXT');\nINSERT INTO config (key, value, remark)\nSELECT 'H_UI_CRT_PATH', '', 'H UI Crt File Path'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'H_UI_CRT_PATH');\nINSERT INTO config (key, value, remark)\nSELECT 'H_UI_KEY_PATH', '', 'H UI Key File Path'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'H_UI_KEY_PATH');\nINSERT INTO config (key, value, remark)\nSELECT 'JWT_SECRET', hex(randomblob(10)), 'JWT Secret'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'JWT_SECRET');\nINSERT INTO config (key, value, remark)\nSELECT 'HYSTERIA2_ENABLE', '0', 'Hysteria2 Switch'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HYSTERIA2_ENABLE');\nINSERT INTO config (key, value, remark)\nSELECT 'HYSTERIA2_CONFIG', '', 'Hysteria2 Config'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HYSTERIA2_CONFIG');\nINSERT INTO config (key, value, remark)\nSELECT 'HYSTERIA2_TRAFFIC_TIME', '1', 'Hysteria2 Traffic Time'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HYSTERIA2_TRAFFIC_TIME');\nINSERT INTO config (key, value, remark)\nSELECT 'HYSTERIA2_CONFIG_REMARK', '', 'Hysteria2 Config Remark'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HYSTERIA2_CONFIG_REMARK');\nINSERT INTO config (key, value, remark)\nSELECT 'HYSTERIA2_CONFIG_PORT_HOPPING', '', 'Hysteria2 Config Port Hopping'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HYSTERIA2_CONFIG_PORT_HOPPING');\nINSERT INTO config (key, value, remark)\nSELECT 'RESET_TRAFFIC_CRON', '', 'Reset Traffic Cron'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'RESET_TRAFFIC_CRON');\nINSERT INTO config (key, value, remark)\nSELECT 'TELEGRAM_ENABLE', '0', 'Telegram Switch'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_ENABLE');\nINSERT INTO config (key, value, remark)\nSELECT 'TELEGRAM_TOKEN', '', 'Telegram Token'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_TOKEN');\nINSERT INTO config (key, value, remark)\nSELECT 'TELEGRAM_CHAT_ID', '', 'Telegram ChatId'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_CHAT_ID');\nINSERT INTO config (key, value, remark)\nSELECT 'TELEGRAM_LOGIN_JOB_ENABLE', '0', 'TELEGRAM LOGIN Notification'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_LOGIN_JOB_ENABLE');\nINSERT INTO config (key, value, remark)\nSELECT 'TELEGRAM_LOGIN_JOB_TEXT', '[time], [username] logged into the panel, IP address is [ip]', 'TELEGRAM LOGIN Notification Text'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_LOGIN_JOB_TEXT');\nINSERT INTO config (key, value, remark)\nSELECT 'CLASH_EXTENSION', '', 'Clash Subscription Extension'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'CLASH_EXTENSION');\nINSERT INTO config (key, value, remark)\nSELECT 'METRICS_ENABLE', '0', 'Metrics Switch'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'METRICS_ENABLE');\nINSERT INTO config (key, value, remark)\nSELECT 'METRICS_TOKEN', '', 'Metrics Token'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'METRICS_TOKEN');\nINSERT INTO config (key, value, remark)\nSELECT 'METRICS_ALLOW_IPS', '', 'Metrics Allowed IPs'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'METRICS_ALLOW_IPS');\nCREATE TABLE IF NOT EXISTS system_metric\n(\n    id           INTEGER PRIMARY KEY AUTOINCREMENT,\n    cpu_percent  REAL    NOT NULL DEFAULT 0,\n    mem_percent  REAL    NOT NULL DEFAULT 0,\n    disks        TEXT    NOT NULL DEFAULT '',\n    nets         TEXT    NOT NULL DEFAULT '',\n    user_total   INTEGER NOT NULL DEFAULT 0,\n    device_total INTEGER NOT NULL DEFAULT 0,\n    sample_time  INTEGER NOT NULL DEFAULT 0,\n    create_time  TIMESTAMP        DEFAULT CURRENT_TIMESTAMP,\n    update_time  TIMESTAMP        DEFAULT CURRENT_TIMESTAMP\n);\nCREATE INDEX IF NOT EXISTS system_metric_sample_time_index ON system_metric (sample_time);\nCREATE TABLE IF NOT EXISTS alert_rule\n(\n    id          INTEGER PRIMARY KEY AUTOINCREMENT,\n    name        TEXT    NOT NULL DEFAULT '',\n    type        TEXT    NOT NULL DEFAULT '',\n    threshold   REAL    NOT NULL DEFAULT 0,\n    duration    INTEGER NOT NULL DEFAULT 0,\n    cooldown    INTEGER NOT NULL DEFAULT 0,\n    enable      INTEGER NOT NULL DEFAULT 1,\n    create_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP,\n    update_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP\n);\nCREATE TABLE IF NOT EXISTS alert_history\n(\n    id          INTEGER PRIMARY KEY AUTOINCREMENT,\n    rule_id     INTEGER NOT NULL DEFAULT 0,\n    rule_name   TEXT    NOT NULL DEFAULT '',\n    type        TEXT    NOT NULL DEFAULT '',\n    state       TEXT    NOT NULL DEFAULT '',\n    value       REAL    NOT NULL DEFAULT 0,\n    message     TEXT    NOT NULL DEFAULT '',\n    notified    INTEGER NOT NULL DEFAULT 0,\n    alert_at    INTEGER NOT NULL DEFAULT 0,\n    create_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP,\n    update_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP\n);\nCREATE INDEX IF NOT EXISTS alert_history_rule_id_index ON alert_history (rule_id);\nCREATE INDEX IF NOT EXISTS alert_history_alert_at_index ON alert_history (alert_at);\nINSERT INTO config (key, value, remark)\nSELECT 'TELEGRAM_2FA_ENABLE', '0', 'Telegram Login Approval'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_2FA_ENABLE');\nCREATE TABLE IF NOT EXISTS login_history\n(\n    id          INTEGER PRIMARY KEY AUTOINCREMENT,\n    username    TEXT    NOT NULL DEFAULT '',\n    ip          TEXT    NOT NULL DEFAULT '',\n    status      TEXT    NOT NULL DEFAULT '',\n    login_at    INTEGER NOT NULL DEFAULT 0,\n    create_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP,\n    update_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP\n);\nCREATE INDEX IF NOT EXISTS login_history_login_at_index ON login_history (login_at);\nINSERT INTO config (key, value, remark)\nSELECT 'TELEGRAM_REPORT_DAILY_CRON', '', 'Telegram Daily Report Cron'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_REPORT_DAILY_CRON');\nINSERT INTO config (key, value, remark)\nSELECT 'TELEGRAM_REPORT_WEEKLY_CRON', '', 'Telegram Weekly Report Cron'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_REPORT_WEEKLY_CRON');\nINSERT INTO config (key, value, remark)\nSELECT 'TELEGRAM_REPORT_MONTHLY_CRON', '', 'Telegram Monthly Report Cron'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_REPORT_MONTHLY_CRON');\nINSERT INTO config (key, value, remark)\nSELECT 'TELEGRAM_REPORT_TOP_N', '5', 'Telegram Report Top N Accounts'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_REPORT_TOP_N');\nINSERT INTO config (key, value, remark)\nSELECT 'TELEGRAM_REPORT_EXPIRE_DAYS', '7', 'Telegram Report Expiring Days'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_REPORT_EXPIRE_DAYS');\nINSERT INTO config (key, value, remark)\nSELECT 'TELEGRAM_REPORT_TEXT', '[period] report ([time])\nTraffic: [traffic]\nTop accounts:\n[top]\nNew accounts: [new]\nExpiring accounts:\n[expiring]\nOver quota accounts:\n[overQuota]\nHysteria2: [hysteria2]\nRestarts: [restarts]\nPeak online devices: [peakDevices]', 'Telegram Report Text'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_REPORT_TEXT');\nCREATE TABLE IF NOT EXISTS report_snapshot\n(\n    id          INTEGER PRIMARY KEY AUTOINCREMENT,\n    period      TEXT    NOT NULL DEFAULT '',\n    traffic     TEXT    NOT NULL DEFAULT '',\n    restarts    INTEGER NOT NULL DEFAULT 0,\n    report_at   INTEGER NOT NULL DEFAULT 0,\n    create_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP,\n    update_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP\n);\nCREATE UNIQUE INDEX IF NOT EXISTS report_snapshot_period_index ON report_snapshot (period);\nINSERT INTO config (key, value, remark)\nSELECT 'TELEGRAM_USER_ENABLE', '0', 'Telegram User Binding'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_USER_ENABLE');\nINSERT INTO config (key, value, remark)\nSELECT 'TELEGRAM_USER_QUOTA_WARN', '80,100', 'Telegram User Quota Warning Percents'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_USER_QUOTA_WARN');\nINSERT INTO config (key, value, remark)\nSELECT 'TELEGRAM_USER_EXPIRE_DAYS', '3', 'Telegram User Expiry Warning Days'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_USER_EXPIRE_DAYS');\nCREATE TABLE IF NOT EXISTS telegram_bind\n(\n    id            INTEGER PRIMARY KEY AUTOINCREMENT,\n    account_id    INTEGER NOT NULL DEFAULT 0,\n    chat_id       INTEGER NOT NULL DEFAULT 0,\n    quota_warned  INTEGER NOT NULL DEFAULT 0,\n    expire_warned INTEGER NOT NULL DEFAULT 0,\n    create_time   TIMESTAMP        DEFAULT CURRENT_TIMESTAMP,\n    update_time   TIMESTAMP        DEFAULT CURRENT_TIMESTAMP\n);\nCREATE UNIQUE INDEX IF NOT EXISTS telegram_bind_account_id_index ON telegram_bind (account_id);\nCREATE UNIQUE INDEX IF NOT EXISTS telegram_bind_chat_id_index ON telegram_bind (chat_id);\nINSERT INTO config (key, value, remark)\nSELECT 'NOTIFY_ROUTES', '{\"login\":[\"telegram\"],\"alert\":[\"telegram\"],\"quota\":[\"telegram\"],\"expiry\":[\"telegram\"],\"hysteria2_crash\":[\"telegram\"],\"report\":[\"telegram\"]}', 'Notification Event Routes'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'NOTIFY_ROUTES');\nINSERT INTO config (key, value, remark)\nSELECT 'NOTIFY_WEBHOOK_URL', '', 'Notification Webhook URL'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'NOTIFY_WEBHOOK_URL');\nINSERT INTO config (key, value, remark)\nSELECT 'NOTIFY_WEBHOOK_SECRET', '', 'Notification Webhook HMAC Secret'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'NOTIFY_WEBHOOK_SECRET');\nINSERT INTO config (key, value, remark)\nSELECT 'NOTIFY_WEBHOOK_RETRY', '3', 'Notification Webhook Retries'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'NOTIFY_WEBHOOK_RETRY');\nINSERT INTO config (key, value, remark)\nSELECT 'NOTIFY_SMTP_HOST', '', 'Notification SMTP Host'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'NOTIFY_SMTP_HOST');\nINSERT INTO config (key, value, remark)\nSELECT 'NOTIFY_SMTP_PORT', '587', 'Notification SMTP Port'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'NOTIFY_SMTP_PORT');\nINSERT INTO config (key, value, remark)\nSELECT 'NOTIFY_SMTP_USERNAME', '', 'Notification SMTP Username'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'NOTIFY_SMTP_USERNAME');\nINSERT INTO config (key, value, remark)\nSELECT 'NOTIFY_SMTP_PASSWORD', '', 'Notification SMTP Password'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'NOTIFY_SMTP_PASSWORD');\nINSERT INTO config (key, value, remark)\nSELECT 'NOTIFY_SMTP_FROM', '', 'Notification SMTP From'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'NOTIFY_SMTP_FROM');\nINSERT INTO config (key, value, remark)\nSELECT 'NOTIFY_SMTP_TO', '', 'Notification SMTP To'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'NOTIFY_SMTP_TO');\nINSERT INTO config (key, value, remark)\nSELECT 'TELEGRAM_PROXY', '', 'Telegram Proxy URL'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_PROXY');\nINSERT INTO config (key, value, remark)\nSELECT 'TELEGRAM_API_ENDPOINT', '', 'Telegram Bot API Endpoint'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_API_ENDPOINT');\nCREATE TABLE IF NOT EXISTS webhook\n(\n    id          INTEGER PRIMARY KEY AUTOINCREMENT,\n    name        TEXT    NOT NULL DEFAULT '',\n    url         TEXT    NOT NULL DEFAULT '',\n    secret      TEXT    NOT NULL DEFAULT '',\n    events      TEXT    NOT NULL DEFAULT '',\n    enable      INTEGER NOT NULL DEFAULT 1,\n    create_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP,\n    update_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP\n);\nCREATE TABLE IF NOT EXISTS webhook_delivery\n(\n    id            INTEGER PRIMARY KEY AUTOINCREMENT,\n    webhook_id    INTEGER NOT NULL DEFAULT 0,\n    event         TEXT    NOT NULL DEFAULT '',\n    payload       TEXT    NOT NULL DEFAULT '',\n    status        TEXT    NOT NULL DEFAULT '',\n    attempts      INTEGER NOT NULL DEFAULT 0,\n    response_code INTEGER NOT NULL DEFAULT 0,\n    error         TEXT    NOT NULL DEFAULT '',\n    next_retry_at INTEGER NOT NULL DEFAULT 0,\n    delivered_at  INTEGER NOT NULL DEFAULT 0,\n    event_at      INTEGER NOT NULL DEFAULT 0,\n    create_time   TIMESTAMP        DEFAULT CURRENT_TIMESTAMP,\n    update_time   TIMESTAMP        DEFAULT CURRENT_TIMESTAMP\n);\nCREATE INDEX IF NOT EXISTS webhook_delivery_webhook_id_index ON webhook_delivery (webhook_id);\nCREATE INDEX IF NOT EXISTS webhook_delivery_status_index ON webhook_delivery (status);\nINSERT INTO config (key, value, remark)\nSELECT 'SUBSCRIBE_UA_RULES', '[{\"pattern\":\"(?i)nekobox\",\"format\":\"nekobox\"},{\"pattern\":\"(?i)sing-box|^sf[aimt]/\",\"format\":\"sing-box\"},{\"pattern\":\"(?i)stash\",\"format\":\"stash\"},{\"pattern\":\"(?i)surge\",\"format\":\"surge\"},{\"pattern\":\"(?i)loon\",\"format\":\"loon\"},{\"pattern\":\"(?i)quantumult\",\"format\":\"quantumultx\"},{\"pattern\":\"(?i)shadowrocket\",\"format\":\"shadowrocket\"},{\"pattern\":\"(?i)clash|mihomo\",\"format\":\"clash\"},{\"pattern\":\"(?i)v2rayn\",\"format\":\"v2rayn\"}]', 'Subscription User-Agent Rules'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SUBSCRIBE_UA_RULES');\nINSERT INTO config (key, value, remark)\nSELECT 'SUBSCRIBE_FORMAT_DISABLED', '', 'Disabled Subscription Formats'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SUBSCRIBE_FORMAT_DISABLED');\nCREATE TABLE IF NOT EXISTS node\n(\n    id            INTEGER PRIMARY KEY AUTOINCREMENT,\n    name          TEXT    NOT NULL DEFAULT '',\n    host          TEXT    NOT NULL DEFAULT '',\n    port          INTEGER NOT NULL DEFAULT 443,\n    port_hopping  TEXT    NOT NULL DEFAULT '',\n    sni           TEXT    NOT NULL DEFAULT '',\n    obfs_password TEXT    NOT NULL DEFAULT '',\n    sort          INTEGER NOT NULL DEFAULT 0,\n    enable        INTEGER NOT NULL DEFAULT 1,\n    create_time   TIMESTAMP        DEFAULT CURRENT_TIMESTAMP,\n    update_time   TIMESTAMP        DEFAULT CURRENT_TIMESTAMP\n);\nCREATE UNIQUE INDEX IF NOT EXISTS node_name_index ON node (name);\nINSERT INTO config (key, value, remark)\nSELECT 'SUBSCRIBE_INFO_ENABLE', '0', 'Subscription Info Nodes Enable'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SUBSCRIBE_INFO_ENABLE');\nINSERT INTO config (key, value, remark)\nSELECT 'SUBSCRIBE_INFO_TEMPLATE', 'Remaining: {remaining}\nExpire: {expire}', 'Subscription Info Node Names'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SUBSCRIBE_INFO_TEMPLATE');\nINSERT INTO config (key, value, remark)\nSELECT 'SUBSCRIBE_ANNOUNCEMENT', '', 'Subscription Announcement'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SUBSCRIBE_ANNOUNCEMENT');\nINSERT INTO config (key, value, remark)\nSELECT 'SUBSCRIBE_NOTICE_TEMPLATE', '{username} is {status}, please contact the administrator', 'Subscription Notice Node Name'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SUBSCRIBE_NOTICE_TEMPLATE');\nINSERT INTO config (key, value, remark)\nSELECT 'HUI_PUBLIC_URL', '', 'H UI Public URL'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HUI_PUBLIC_URL');\nINSERT INTO config (key, value, remark)\nSELECT 'HUI_TRUSTED_PROXIES', '', 'H UI Trusted Proxies'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HUI_TRUSTED_PROXIES');\nINSERT INTO config (key, value, remark)\nSELECT 'SUBSCRIBE_SERVER', '', 'Subscription Server Address'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SUBSCRIBE_SERVER');\nINSERT INTO config (key, value, remark)\nSELECT 'SUBSCRIBE_PORT', '', 'Subscription Server Port'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SUBSCRIBE_PORT');\nCREATE TABLE IF NOT EXISTS subscribe_log\n(\n    id          INTEGER PRIMARY KEY AUTOINCREMENT,\n    account_id  INTEGER NOT NULL DEFAULT 0,\n    source      TEXT    NOT NULL DEFAULT '',\n    ip          TEXT    NOT NULL DEFAULT '',\n    user_agent  TEXT    NOT NULL DEFAULT '',\n    format      TEXT    NOT NULL DEFAULT '',\n    access_at   INTEGER NOT NULL DEFAULT 0,\n    create_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP,\n    update_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP\n);\nCREATE INDEX IF NOT EXISTS subscribe_log_account_id_index ON subscribe_log (account_id, access_at);\nCREATE INDEX IF NOT EXISTS subscribe_log_access_at_index ON subscribe_log (access_at);\nCREATE TABLE IF NOT EXISTS share_flag\n(\n    id          INTEGER PRIMARY KEY AUTOINCREMENT,\n    account_id  INTEGER NOT NULL DEFAULT 0,\n    ip_count    INTEGER NOT NULL DEFAULT 0,\n    ips         TEXT    NOT NULL DEFAULT '',\n    action      TEXT    NOT NULL DEFAULT '',\n    flag_at     INTEGER NOT NULL DEFAULT 0,\n    create_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP,\n    update_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP\n);\nCREATE INDEX IF NOT EXISTS share_flag_account_id_index ON share_flag (account_id);\nINSERT INTO config (key, value, remark)\nSELECT 'SUBSCRIBE_LOG_DAYS', '30', 'Subscription Access Log Retention Days'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SUBSCRIBE_LOG_DAYS');\nINSERT INTO config (key, value, remark)\nSELECT 'SHARE_DETECT_ENABLE', '0', 'Sharing Detection Enable'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SHARE_DETECT_ENABLE');\nINSERT INTO config (key, value, remark)\nSELECT 'SHARE_DETECT_MAX_IPS', '5', 'Sharing Detection Max Distinct IPs'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SHARE_DETECT_MAX_IPS');\nINSERT INTO config (key, value, remark)\nSELECT 'SHARE_DETECT_WINDOW', '60', 'Sharing Detection Window Minutes'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SHARE_DETECT_WINDOW');\nINSERT INTO config (key, value, remark)\nSELECT 'SHARE_DETECT_NETWORK', 'ip', 'Sharing Detection Counting Unit'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SHARE_DETECT_NETWORK');\nINSERT INTO config (key, value, remark)\nSELECT 'SHARE_DETECT_ACTION', 'notify', 'Sharing Detection Action'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SHARE_DETECT_ACTION');\nINSERT INTO config (key, value, remark)\nSELECT 'SHARE_DETECT_KICK_MINUTES', '10', 'Sharing Detection Kick Minutes'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SHARE_DETECT_KICK_MINUTES');\nINSERT INTO config (key, value, remark)\nSELECT 'HYSTERIA2_CLIENT_SOCKS5', '127.0.0.1:1080', 'Hysteria2 Client SOCKS5 Listen'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HYSTERIA2_CLIENT_SOCKS5');\nINSERT INTO config (key, value, remark)\nSELECT 'HYSTERIA2_CLIENT_HTTP', '127.0.0.1:8080', 'Hysteria2 Client HTTP Listen'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HYSTERIA2_CLIENT_HTTP');\nINSERT INTO config (key, value, remark)\nSELECT 'CERT_EXPIRE_WARN_DAYS', '14', 'Certificate Expiry Warning Days'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'CERT_EXPIRE_WARN_DAYS');\nINSERT INTO config (key, value, remark)\nSELECT 'HUI_CERT_FROM_HYSTERIA2', '0', 'H UI Use Hysteria2 Certificate'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HUI_CERT_FROM_HYSTERIA2');\nINSERT INTO config (key, value, remark)\nSELECT 'HUI_REAL_IP_HEADER', 'X-Forwarded-For', 'H UI Real IP Header'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HUI_REAL_IP_HEADER');\nINSERT INTO config (key, value, remark)\nSELECT 'HUI_BIND_ADDRESS', '', 'H UI Bind Address'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HUI_BIND_ADDRESS');\nINSERT INTO config (key, value, remark)\nSELECT 'HUI_UNIX_SOCKET', '', 'H UI Unix Socket Path'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HUI_UNIX_SOCKET');\nINSERT INTO config (key, value, remark)\nSELECT 'FILTER_USER_AGENTS', 'fofa,shodan,curl,wget', 'Blocked User-Agent Keywords'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'FILTER_USER_AGENTS');\nINSERT INTO config (key, value, remark)\nSELECT 'SUBSCRIBE_FILTER_USER_AGENTS', 'fofa,shodan', 'Subscription Blocked User-Agent Keywords'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SUBSCRIBE_FILTER_USER_AGENTS');\nINSERT INTO config (key, value, remark)\nSELECT 'ADMIN_ALLOW_IPS', '', 'Admin Allowed IPs'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'ADMIN_ALLOW_IPS');\nINSERT INTO config (key, value, remark)\nSELECT 'ADMIN_DENY_IPS', '', 'Admin Denied IPs'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'ADMIN_DENY_IPS');\nINSERT INTO config (key, value, remark)\nSELECT 'ADMIN_ALLOW_COUNTRIES', '', 'Admin Allowed Countries'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'ADMIN_ALLOW_COUNTRIES');\nINSERT INTO config (key, value, remark)\nSELECT 'ADMIN_DENY_COUNTRIES', '', 'Admin Denied Countries'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'ADMIN_DENY_COUNTRIES');\nINSERT INTO config (key, value, remark)\nSELECT 'PUBLIC_ALLOW_IPS', '', 'Public Allowed IPs'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'PUBLIC_ALLOW_IPS');\nINSERT INTO config (key, value, remark)\nSELECT 'PUBLIC_DENY_IPS', '', 'Public Denied IPs'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'PUBLIC_DENY_IPS');\nINSERT INTO config (key, value, remark)\nSELECT 'PUBLIC_ALLOW_COUNTRIES', '', 'Public Allowed Countries'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'PUBLIC_ALLOW_COUNTRIES');\nINSERT INTO config (key, value, remark)\nSELECT 'PUBLIC_DENY_COUNTRIES', '', 'Public Denied Countries'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'PUBLIC_DENY_COUNTRIES');\nINSERT INTO config (key, value, remark)\nSELECT 'GEOIP_DB_PATH', '', 'GeoIP MMDB File Path'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'GEOIP_DB_PATH');\nINSERT INTO config (key, value, remark)\nSELECT 'HUI_UNIX_SOCKET_MODE', '0660', 'H UI Unix Socket File Mode'\n    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HUI_UNIX_SOCKET_MODE');"
 
 var sqliteDB *gorm.DB
 
@@ -117,3 +117,19 @@ func Paginate(pageNum *int64, pageSize *int64) func(db *gorm.DB) *gorm.DB {
 		return db.Offset(int((num - 1) * size)).Limit(int(size))
 	}
 }
+
+func PingSqliteDB() error {
+	if sqliteDB == nil {
+		return errors.New("sqlite not initialized")
+	}
+	db, err := sqliteDB.DB()
+	if err != nil {
+		logrus.Errorf("sqlite err: %v", err)
+		return errors.New("sqlite err")
+	}
+	if err = db.Ping(); err != nil {
+		logrus.Errorf("sqlite ping err: %v", err)
+		return errors.New("sqlite ping err")
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package dao
+
+import (
+	"errors"
+	"github.com/sirupsen/logrus"
+	"h-ui/model/bo"
+	"h-ui/model/constant"
+	"h-ui/model/dto"
+	"h-ui/model/entity"
+)
+
+func SaveSubscribeLog(subscribeLog entity.SubscribeLog) (int64, error) {
+	if tx := sqliteDB.Save(&subscribeLog); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return 0, errors.New(constant.SysError)
+	}
+	return *subscribeLog.Id, nil
+}
+
+func ListSubscribeLog(query interface{}, args ...interface{}) ([]entity.SubscribeLog, error) {
+	var subscribeLogs []entity.SubscribeLog
+	if tx := sqliteDB.Model(&entity.SubscribeLog{}).
+		Where(query, args...).Order("access_at").Find(&subscribeLogs); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return subscribeLogs, errors.New(constant.SysError)
+	}
+	return subscribeLogs, nil
+}
+
+func DeleteSubscribeLog(query interface{}, args ...interface{}) error {
+	if tx := sqliteDB.Where(query, args...).Delete(&entity.SubscribeLog{}); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return errors.New(constant.SysError)
+	}
+	return nil
+}
+
+func PageSubscribeLog(subscribeLogPageDto dto.SubscribeLogPageDto) ([]entity.SubscribeLog, int64, error) {
+	var subscribeLogs []entity.SubscribeLog
+	var total int64
+	tx := sqliteDB.Model(&entity.SubscribeLog{})
+	if subscribeLogPageDto.AccountId != nil {
+		tx.Where("account_id = ?", *subscribeLogPageDto.AccountId)
+	}
+	if subscribeLogPageDto.Source != nil && *subscribeLogPageDto.Source != "" {
+		tx.Where("source = ?", *subscribeLogPageDto.Source)
+	}
+	if subscribeLogPageDto.Ip != nil && *subscribeLogPageDto.Ip != "" {
+		tx.Where("ip = ?", *subscribeLogPageDto.Ip)
+	}
+	if subscribeLogPageDto.StartTime != nil {
+		tx.Where("access_at >= ?", *subscribeLogPageDto.StartTime)
+	}
+	if subscribeLogPageDto.EndTime != nil {
+		tx.Where("access_at <= ?", *subscribeLogPageDto.EndTime)
+	}
+	tx.Count(&total)
+	if tx.Scopes(Paginate(subscribeLogPageDto.PageNum, subscribeLogPageDto.PageSize)).
+		Order("access_at desc").
+		Find(&subscribeLogs); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return subscribeLogs, 0, errors.New(constant.SysError)
+	}
+	return subscribeLogs, total, nil
+}
+
+// StatSubscribeLog 按 column 分组统计账户的访问次数和首末次访问时间
+func StatSubscribeLog(accountId int64, column string, startAt int64) ([]bo.SubscribeLogStat, error) {
+	var stats []bo.SubscribeLogStat
+	if column != "ip" && column != "user_agent" {
+		return stats, errors.New(constant.SysError)
+	}
+	if tx := sqliteDB.Model(&entity.SubscribeLog{}).
+		Select(column+" as value, count(*) as count, min(access_at) as first_at, max(access_at) as last_at").
+		Where("account_id = ? and access_at >= ?", accountId, startAt).
+		Group(column).
+		Order("last_at desc").
+		Scan(&stats); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return stats, errors.New(constant.SysError)
+	}
+	return stats, nil
+}
+
+func SaveShareFlag(shareFlag entity.ShareFlag) (int64, error) {
+	if tx := sqliteDB.Save(&shareFlag); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return 0, errors.New(constant.SysError)
+	}
+	return *shareFlag.Id, nil
+}
+
+func ListShareFlag(query interface{}, args ...interface{}) ([]entity.ShareFlag, error) {
+	var shareFlags []entity.ShareFlag
+	if tx := sqliteDB.Model(&entity.ShareFlag{}).
+		Where(query, args...).Order("id").Find(&shareFlags); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return shareFlags, errors.New(constant.SysError)
+	}
+	return shareFlags, nil
+}
+
+func DeleteShareFlag(query interface{}, args ...interface{}) error {
+	if tx := sqliteDB.Where(query, args...).Delete(&entity.ShareFlag{}); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return errors.New(constant.SysError)
+	}
+	return nil
+}
+
+func PageShareFlag(shareFlagPageDto dto.ShareFlagPageDto) ([]entity.ShareFlag, int64, error) {
+	var shareFlags []entity.ShareFlag
+	var total int64
+	tx := sqliteDB.Model(&entity.ShareFlag{})
+	if shareFlagPageDto.AccountId != nil {
+		tx.Where("account_id = ?", *shareFlagPageDto.AccountId)
+	}
+	if shareFlagPageDto.StartTime != nil {
+		tx.Where("flag_at >= ?", *shareFlagPageDto.StartTime)
+	}
+	if shareFlagPageDto.EndTime != nil {
+		tx.Where("flag_at <= ?", *shareFlagPageDto.EndTime)
+	}
+	tx.Count(&total)
+	if tx.Scopes(Paginate(shareFlagPageDto.PageNum, shareFlagPageDto.PageSize)).
+		Order("flag_at desc").
+		Find(&shareFlags); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return shareFlags, 0, errors.New(constant.SysError)
+	}
+	return shareFlags, total, nil
+}
@@ -0,0 +1,49 @@
+package dao
+
+import (
+	"errors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"h-ui/model/constant"
+	"h-ui/model/entity"
+)
+
+func SaveSystemMetric(systemMetric entity.SystemMetric) (int64, error) {
+	if tx := sqliteDB.Save(&systemMetric); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return 0, errors.New(constant.SysError)
+	}
+	return *systemMetric.Id, nil
+}
+
+// TrimSystemMetric 只保留最新的 maxRows 条记录
+func TrimSystemMetric(maxRows int64) error {
+	if tx := sqliteDB.Exec("DELETE FROM system_metric WHERE id <= (SELECT max(id) FROM system_metric) - ?", maxRows); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return errors.New(constant.SysError)
+	}
+	return nil
+}
+
+func ListSystemMetric(query interface{}, args ...interface{}) ([]entity.SystemMetric, error) {
+	var systemMetrics []entity.SystemMetric
+	if tx := sqliteDB.Model(&entity.SystemMetric{}).
+		Where(query, args...).Order("sample_time").Find(&systemMetrics); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return systemMetrics, errors.New(constant.SysError)
+	}
+	return systemMetrics, nil
+}
+
+func GetLatestSystemMetric() (entity.SystemMetric, error) {
+	var systemMetric entity.SystemMetric
+	if tx := sqliteDB.Model(&entity.SystemMetric{}).
+		Order("sample_time desc").First(&systemMetric); tx.Error != nil {
+		if tx.Error == gorm.ErrRecordNotFound {
+			return systemMetric, errors.New("system metric not exist")
+		}
+		logrus.Errorf("%v", tx.Error)
+		return systemMetric, errors.New(constant.SysError)
+	}
+	return systemMetric, nil
+}
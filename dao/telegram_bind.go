@@ -0,0 +1,57 @@
+package dao
+
+import (
+	"errors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"h-ui/model/constant"
+	"h-ui/model/entity"
+)
+
+func SaveTelegramBind(telegramBind entity.TelegramBind) error {
+	if tx := sqliteDB.Save(&telegramBind); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return errors.New(constant.SysError)
+	}
+	return nil
+}
+
+func UpdateTelegramBind(ids []int64, updates map[string]interface{}) error {
+	if tx := sqliteDB.Model(&entity.TelegramBind{}).
+		Where("id in ?", ids).Updates(updates); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return errors.New(constant.SysError)
+	}
+	return nil
+}
+
+func DeleteTelegramBind(query interface{}, args ...interface{}) error {
+	if tx := sqliteDB.Where(query, args...).Delete(&entity.TelegramBind{}); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return errors.New(constant.SysError)
+	}
+	return nil
+}
+
+func GetTelegramBind(query interface{}, args ...interface{}) (entity.TelegramBind, error) {
+	var telegramBind entity.TelegramBind
+	if tx := sqliteDB.Model(&entity.TelegramBind{}).
+		Where(query, args...).First(&telegramBind); tx.Error != nil {
+		if tx.Error == gorm.ErrRecordNotFound {
+			return telegramBind, errors.New("telegram bind not exist")
+		}
+		logrus.Errorf("%v", tx.Error)
+		return telegramBind, errors.New(constant.SysError)
+	}
+	return telegramBind, nil
+}
+
+func ListTelegramBind(query interface{}, args ...interface{}) ([]entity.TelegramBind, error) {
+	var telegramBinds []entity.TelegramBind
+	if tx := sqliteDB.Model(&entity.TelegramBind{}).
+		Where(query, args...).Find(&telegramBinds); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return telegramBinds, errors.New(constant.SysError)
+	}
+	return telegramBinds, nil
+}
@@ -0,0 +1,145 @@
+package dao
+
+import (
+	"errors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"h-ui/model/constant"
+	"h-ui/model/dto"
+	"h-ui/model/entity"
+	"time"
+)
+
+func SaveWebhook(webhook entity.Webhook) (int64, error) {
+	if tx := sqliteDB.Save(&webhook); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return 0, errors.New(constant.SysError)
+	}
+	return *webhook.Id, nil
+}
+
+func UpdateWebhook(ids []int64, updates map[string]interface{}) error {
+	if len(updates) > 0 {
+		updates["update_time"] = time.Now().Format("2006-01-02 15:04:05")
+		if tx := sqliteDB.Model(&entity.Webhook{}).
+			Where("id in ?", ids).
+			Updates(updates); tx.Error != nil {
+			logrus.Errorf("%v", tx.Error)
+			return errors.New(constant.SysError)
+		}
+	}
+	return nil
+}
+
+func DeleteWebhook(ids []int64) error {
+	if tx := sqliteDB.Where("id in ?", ids).Delete(&entity.Webhook{}); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return errors.New(constant.SysError)
+	}
+	return nil
+}
+
+func GetWebhook(query interface{}, args ...interface{}) (entity.Webhook, error) {
+	var webhook entity.Webhook
+	if tx := sqliteDB.Model(&entity.Webhook{}).
+		Where(query, args...).First(&webhook); tx.Error != nil {
+		if tx.Error == gorm.ErrRecordNotFound {
+			return webhook, errors.New("webhook not exist")
+		}
+		logrus.Errorf("%v", tx.Error)
+		return webhook, errors.New(constant.SysError)
+	}
+	return webhook, nil
+}
+
+func ListWebhook(query interface{}, args ...interface{}) ([]entity.Webhook, error) {
+	var webhooks []entity.Webhook
+	if tx := sqliteDB.Model(&entity.Webhook{}).
+		Where(query, args...).Order("id").Find(&webhooks); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return webhooks, errors.New(constant.SysError)
+	}
+	return webhooks, nil
+}
+
+func SaveWebhookDelivery(webhookDelivery entity.WebhookDelivery) (int64, error) {
+	if tx := sqliteDB.Save(&webhookDelivery); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return 0, errors.New(constant.SysError)
+	}
+	return *webhookDelivery.Id, nil
+}
+
+func UpdateWebhookDelivery(ids []int64, updates map[string]interface{}) error {
+	if len(updates) > 0 {
+		updates["update_time"] = time.Now().Format("2006-01-02 15:04:05")
+		if tx := sqliteDB.Model(&entity.WebhookDelivery{}).
+			Where("id in ?", ids).
+			Updates(updates); tx.Error != nil {
+			logrus.Errorf("%v", tx.Error)
+			return errors.New(constant.SysError)
+		}
+	}
+	return nil
+}
+
+func GetWebhookDelivery(query interface{}, args ...interface{}) (entity.WebhookDelivery, error) {
+	var webhookDelivery entity.WebhookDelivery
+	if tx := sqliteDB.Model(&entity.WebhookDelivery{}).
+		Where(query, args...).First(&webhookDelivery); tx.Error != nil {
+		if tx.Error == gorm.ErrRecordNotFound {
+			return webhookDelivery, errors.New("webhook delivery not exist")
+		}
+		logrus.Errorf("%v", tx.Error)
+		return webhookDelivery, errors.New(constant.SysError)
+	}
+	return webhookDelivery, nil
+}
+
+func ListWebhookDelivery(query interface{}, args ...interface{}) ([]entity.WebhookDelivery, error) {
+	var webhookDeliveries []entity.WebhookDelivery
+	if tx := sqliteDB.Model(&entity.WebhookDelivery{}).
+		Where(query, args...).Order("id").Find(&webhookDeliveries); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return webhookDeliveries, errors.New(constant.SysError)
+	}
+	return webhookDeliveries, nil
+}
+
+// TrimWebhookDelivery 只保留最新的 maxRows 条投递记录
+func TrimWebhookDelivery(maxRows int64) error {
+	if tx := sqliteDB.Exec("DELETE FROM webhook_delivery WHERE id <= (SELECT max(id) FROM webhook_delivery) - ?", maxRows); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return errors.New(constant.SysError)
+	}
+	return nil
+}
+
+func PageWebhookDelivery(webhookDeliveryPageDto dto.WebhookDeliveryPageDto) ([]entity.WebhookDelivery, int64, error) {
+	var webhookDeliveries []entity.WebhookDelivery
+	var total int64
+	tx := sqliteDB.Model(&entity.WebhookDelivery{})
+	if webhookDeliveryPageDto.WebhookId != nil {
+		tx.Where("webhook_id = ?", *webhookDeliveryPageDto.WebhookId)
+	}
+	if webhookDeliveryPageDto.Event != nil && *webhookDeliveryPageDto.Event != "" {
+		tx.Where("event = ?", *webhookDeliveryPageDto.Event)
+	}
+	if webhookDeliveryPageDto.Status != nil && *webhookDeliveryPageDto.Status != "" {
+		tx.Where("status = ?", *webhookDeliveryPageDto.Status)
+	}
+	if webhookDeliveryPageDto.StartTime != nil {
+		tx.Where("event_at >= ?", *webhookDeliveryPageDto.StartTime)
+	}
+	if webhookDeliveryPageDto.EndTime != nil {
+		tx.Where("event_at <= ?", *webhookDeliveryPageDto.EndTime)
+	}
+	tx.Count(&total)
+	if tx.Scopes(Paginate(webhookDeliveryPageDto.PageNum, webhookDeliveryPageDto.PageSize)).
+		Order("id desc").
+		Find(&webhookDeliveries); tx.Error != nil {
+		logrus.Errorf("%v", tx.Error)
+		return webhookDeliveries, 0, errors.New(constant.SysError)
+	}
+	return webhookDeliveries, total, nil
+}
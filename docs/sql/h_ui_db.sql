@@ -86,4 +86,345 @@ SELECT 'TELEGRAM_LOGIN_JOB_TEXT', '[time], [username] logged into the panel, IP
     WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_LOGIN_JOB_TEXT');
 INSERT INTO config (key, value, remark)
 SELECT 'CLASH_EXTENSION', '', 'Clash Subscription Extension'
-    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'CLASH_EXTENSION');
\ No newline at end of file
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'CLASH_EXTENSION');
+INSERT INTO config (key, value, remark)
+SELECT 'METRICS_ENABLE', '0', 'Metrics Switch'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'METRICS_ENABLE');
+INSERT INTO config (key, value, remark)
+SELECT 'METRICS_TOKEN', '', 'Metrics Token'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'METRICS_TOKEN');
+INSERT INTO config (key, value, remark)
+SELECT 'METRICS_ALLOW_IPS', '', 'Metrics Allowed IPs'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'METRICS_ALLOW_IPS');
+CREATE TABLE IF NOT EXISTS system_metric
+(
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    cpu_percent  REAL    NOT NULL DEFAULT 0,
+    mem_percent  REAL    NOT NULL DEFAULT 0,
+    disks        TEXT    NOT NULL DEFAULT '',
+    nets         TEXT    NOT NULL DEFAULT '',
+    user_total   INTEGER NOT NULL DEFAULT 0,
+    device_total INTEGER NOT NULL DEFAULT 0,
+    sample_time  INTEGER NOT NULL DEFAULT 0,
+    create_time  TIMESTAMP        DEFAULT CURRENT_TIMESTAMP,
+    update_time  TIMESTAMP        DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS system_metric_sample_time_index ON system_metric (sample_time);
+CREATE TABLE IF NOT EXISTS alert_rule
+(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    name        TEXT    NOT NULL DEFAULT '',
+    type        TEXT    NOT NULL DEFAULT '',
+    threshold   REAL    NOT NULL DEFAULT 0,
+    duration    INTEGER NOT NULL DEFAULT 0,
+    cooldown    INTEGER NOT NULL DEFAULT 0,
+    enable      INTEGER NOT NULL DEFAULT 1,
+    create_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP,
+    update_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE IF NOT EXISTS alert_history
+(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    rule_id     INTEGER NOT NULL DEFAULT 0,
+    rule_name   TEXT    NOT NULL DEFAULT '',
+    type        TEXT    NOT NULL DEFAULT '',
+    state       TEXT    NOT NULL DEFAULT '',
+    value       REAL    NOT NULL DEFAULT 0,
+    message     TEXT    NOT NULL DEFAULT '',
+    notified    INTEGER NOT NULL DEFAULT 0,
+    alert_at    INTEGER NOT NULL DEFAULT 0,
+    create_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP,
+    update_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS alert_history_rule_id_index ON alert_history (rule_id);
+CREATE INDEX IF NOT EXISTS alert_history_alert_at_index ON alert_history (alert_at);
+INSERT INTO config (key, value, remark)
+SELECT 'TELEGRAM_2FA_ENABLE', '0', 'Telegram Login Approval'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_2FA_ENABLE');
+CREATE TABLE IF NOT EXISTS login_history
+(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    username    TEXT    NOT NULL DEFAULT '',
+    ip          TEXT    NOT NULL DEFAULT '',
+    status      TEXT    NOT NULL DEFAULT '',
+    login_at    INTEGER NOT NULL DEFAULT 0,
+    create_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP,
+    update_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS login_history_login_at_index ON login_history (login_at);
+INSERT INTO config (key, value, remark)
+SELECT 'TELEGRAM_REPORT_DAILY_CRON', '', 'Telegram Daily Report Cron'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_REPORT_DAILY_CRON');
+INSERT INTO config (key, value, remark)
+SELECT 'TELEGRAM_REPORT_WEEKLY_CRON', '', 'Telegram Weekly Report Cron'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_REPORT_WEEKLY_CRON');
+INSERT INTO config (key, value, remark)
+SELECT 'TELEGRAM_REPORT_MONTHLY_CRON', '', 'Telegram Monthly Report Cron'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_REPORT_MONTHLY_CRON');
+INSERT INTO config (key, value, remark)
+SELECT 'TELEGRAM_REPORT_TOP_N', '5', 'Telegram Report Top N Accounts'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_REPORT_TOP_N');
+INSERT INTO config (key, value, remark)
+SELECT 'TELEGRAM_REPORT_EXPIRE_DAYS', '7', 'Telegram Report Expiring Days'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_REPORT_EXPIRE_DAYS');
+INSERT INTO config (key, value, remark)
+SELECT 'TELEGRAM_REPORT_TEXT', '[period] report ([time])
+Traffic: [traffic]
+Top accounts:
+[top]
+New accounts: [new]
+Expiring accounts:
+[expiring]
+Over quota accounts:
+[overQuota]
+Hysteria2: [hysteria2]
+Restarts: [restarts]
+Peak online devices: [peakDevices]', 'Telegram Report Text'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_REPORT_TEXT');
+CREATE TABLE IF NOT EXISTS report_snapshot
+(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    period      TEXT    NOT NULL DEFAULT '',
+    traffic     TEXT    NOT NULL DEFAULT '',
+    restarts    INTEGER NOT NULL DEFAULT 0,
+    report_at   INTEGER NOT NULL DEFAULT 0,
+    create_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP,
+    update_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS report_snapshot_period_index ON report_snapshot (period);
+INSERT INTO config (key, value, remark)
+SELECT 'TELEGRAM_USER_ENABLE', '0', 'Telegram User Binding'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_USER_ENABLE');
+INSERT INTO config (key, value, remark)
+SELECT 'TELEGRAM_USER_QUOTA_WARN', '80,100', 'Telegram User Quota Warning Percents'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_USER_QUOTA_WARN');
+INSERT INTO config (key, value, remark)
+SELECT 'TELEGRAM_USER_EXPIRE_DAYS', '3', 'Telegram User Expiry Warning Days'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_USER_EXPIRE_DAYS');
+CREATE TABLE IF NOT EXISTS telegram_bind
+(
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id    INTEGER NOT NULL DEFAULT 0,
+    chat_id       INTEGER NOT NULL DEFAULT 0,
+    quota_warned  INTEGER NOT NULL DEFAULT 0,
+    expire_warned INTEGER NOT NULL DEFAULT 0,
+    create_time   TIMESTAMP        DEFAULT CURRENT_TIMESTAMP,
+    update_time   TIMESTAMP        DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS telegram_bind_account_id_index ON telegram_bind (account_id);
+CREATE UNIQUE INDEX IF NOT EXISTS telegram_bind_chat_id_index ON telegram_bind (chat_id);
+INSERT INTO config (key, value, remark)
+SELECT 'NOTIFY_ROUTES', '{"login":["telegram"],"alert":["telegram"],"quota":["telegram"],"expiry":["telegram"],"hysteria2_crash":["telegram"],"report":["telegram"]}', 'Notification Event Routes'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'NOTIFY_ROUTES');
+INSERT INTO config (key, value, remark)
+SELECT 'NOTIFY_WEBHOOK_URL', '', 'Notification Webhook URL'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'NOTIFY_WEBHOOK_URL');
+INSERT INTO config (key, value, remark)
+SELECT 'NOTIFY_WEBHOOK_SECRET', '', 'Notification Webhook HMAC Secret'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'NOTIFY_WEBHOOK_SECRET');
+INSERT INTO config (key, value, remark)
+SELECT 'NOTIFY_WEBHOOK_RETRY', '3', 'Notification Webhook Retries'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'NOTIFY_WEBHOOK_RETRY');
+INSERT INTO config (key, value, remark)
+SELECT 'NOTIFY_SMTP_HOST', '', 'Notification SMTP Host'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'NOTIFY_SMTP_HOST');
+INSERT INTO config (key, value, remark)
+SELECT 'NOTIFY_SMTP_PORT', '587', 'Notification SMTP Port'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'NOTIFY_SMTP_PORT');
+INSERT INTO config (key, value, remark)
+SELECT 'NOTIFY_SMTP_USERNAME', '', 'Notification SMTP Username'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'NOTIFY_SMTP_USERNAME');
+INSERT INTO config (key, value, remark)
+SELECT 'NOTIFY_SMTP_PASSWORD', '', 'Notification SMTP Password'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'NOTIFY_SMTP_PASSWORD');
+INSERT INTO config (key, value, remark)
+SELECT 'NOTIFY_SMTP_FROM', '', 'Notification SMTP From'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'NOTIFY_SMTP_FROM');
+INSERT INTO config (key, value, remark)
+SELECT 'NOTIFY_SMTP_TO', '', 'Notification SMTP To'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'NOTIFY_SMTP_TO');
+INSERT INTO config (key, value, remark)
+SELECT 'TELEGRAM_PROXY', '', 'Telegram Proxy URL'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_PROXY');
+INSERT INTO config (key, value, remark)
+SELECT 'TELEGRAM_API_ENDPOINT', '', 'Telegram Bot API Endpoint'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'TELEGRAM_API_ENDPOINT');
+CREATE TABLE IF NOT EXISTS webhook
+(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    name        TEXT    NOT NULL DEFAULT '',
+    url         TEXT    NOT NULL DEFAULT '',
+    secret      TEXT    NOT NULL DEFAULT '',
+    events      TEXT    NOT NULL DEFAULT '',
+    enable      INTEGER NOT NULL DEFAULT 1,
+    create_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP,
+    update_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE IF NOT EXISTS webhook_delivery
+(
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_id    INTEGER NOT NULL DEFAULT 0,
+    event         TEXT    NOT NULL DEFAULT '',
+    payload       TEXT    NOT NULL DEFAULT '',
+    status        TEXT    NOT NULL DEFAULT '',
+    attempts      INTEGER NOT NULL DEFAULT 0,
+    response_code INTEGER NOT NULL DEFAULT 0,
+    error         TEXT    NOT NULL DEFAULT '',
+    next_retry_at INTEGER NOT NULL DEFAULT 0,
+    delivered_at  INTEGER NOT NULL DEFAULT 0,
+    event_at      INTEGER NOT NULL DEFAULT 0,
+    create_time   TIMESTAMP        DEFAULT CURRENT_TIMESTAMP,
+    update_time   TIMESTAMP        DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS webhook_delivery_webhook_id_index ON webhook_delivery (webhook_id);
+CREATE INDEX IF NOT EXISTS webhook_delivery_status_index ON webhook_delivery (status);
+INSERT INTO config (key, value, remark)
+SELECT 'SUBSCRIBE_UA_RULES', '[{"pattern":"(?i)nekobox","format":"nekobox"},{"pattern":"(?i)sing-box|^sf[aimt]/","format":"sing-box"},{"pattern":"(?i)stash","format":"stash"},{"pattern":"(?i)surge","format":"surge"},{"pattern":"(?i)loon","format":"loon"},{"pattern":"(?i)quantumult","format":"quantumultx"},{"pattern":"(?i)shadowrocket","format":"shadowrocket"},{"pattern":"(?i)clash|mihomo","format":"clash"},{"pattern":"(?i)v2rayn","format":"v2rayn"}]', 'Subscription User-Agent Rules'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SUBSCRIBE_UA_RULES');
+INSERT INTO config (key, value, remark)
+SELECT 'SUBSCRIBE_FORMAT_DISABLED', '', 'Disabled Subscription Formats'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SUBSCRIBE_FORMAT_DISABLED');
+CREATE TABLE IF NOT EXISTS node
+(
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    name          TEXT    NOT NULL DEFAULT '',
+    host          TEXT    NOT NULL DEFAULT '',
+    port          INTEGER NOT NULL DEFAULT 443,
+    port_hopping  TEXT    NOT NULL DEFAULT '',
+    sni           TEXT    NOT NULL DEFAULT '',
+    obfs_password TEXT    NOT NULL DEFAULT '',
+    sort          INTEGER NOT NULL DEFAULT 0,
+    enable        INTEGER NOT NULL DEFAULT 1,
+    create_time   TIMESTAMP        DEFAULT CURRENT_TIMESTAMP,
+    update_time   TIMESTAMP        DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS node_name_index ON node (name);
+INSERT INTO config (key, value, remark)
+SELECT 'SUBSCRIBE_INFO_ENABLE', '0', 'Subscription Info Nodes Enable'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SUBSCRIBE_INFO_ENABLE');
+INSERT INTO config (key, value, remark)
+SELECT 'SUBSCRIBE_INFO_TEMPLATE', 'Remaining: {remaining}
+Expire: {expire}', 'Subscription Info Node Names'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SUBSCRIBE_INFO_TEMPLATE');
+INSERT INTO config (key, value, remark)
+SELECT 'SUBSCRIBE_ANNOUNCEMENT', '', 'Subscription Announcement'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SUBSCRIBE_ANNOUNCEMENT');
+INSERT INTO config (key, value, remark)
+SELECT 'SUBSCRIBE_NOTICE_TEMPLATE', '{username} is {status}, please contact the administrator', 'Subscription Notice Node Name'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SUBSCRIBE_NOTICE_TEMPLATE');
+INSERT INTO config (key, value, remark)
+SELECT 'HUI_PUBLIC_URL', '', 'H UI Public URL'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HUI_PUBLIC_URL');
+INSERT INTO config (key, value, remark)
+SELECT 'HUI_TRUSTED_PROXIES', '', 'H UI Trusted Proxies'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HUI_TRUSTED_PROXIES');
+INSERT INTO config (key, value, remark)
+SELECT 'SUBSCRIBE_SERVER', '', 'Subscription Server Address'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SUBSCRIBE_SERVER');
+INSERT INTO config (key, value, remark)
+SELECT 'SUBSCRIBE_PORT', '', 'Subscription Server Port'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SUBSCRIBE_PORT');
+CREATE TABLE IF NOT EXISTS subscribe_log
+(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id  INTEGER NOT NULL DEFAULT 0,
+    source      TEXT    NOT NULL DEFAULT '',
+    ip          TEXT    NOT NULL DEFAULT '',
+    user_agent  TEXT    NOT NULL DEFAULT '',
+    format      TEXT    NOT NULL DEFAULT '',
+    access_at   INTEGER NOT NULL DEFAULT 0,
+    create_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP,
+    update_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS subscribe_log_account_id_index ON subscribe_log (account_id, access_at);
+CREATE INDEX IF NOT EXISTS subscribe_log_access_at_index ON subscribe_log (access_at);
+CREATE TABLE IF NOT EXISTS share_flag
+(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id  INTEGER NOT NULL DEFAULT 0,
+    ip_count    INTEGER NOT NULL DEFAULT 0,
+    ips         TEXT    NOT NULL DEFAULT '',
+    action      TEXT    NOT NULL DEFAULT '',
+    flag_at     INTEGER NOT NULL DEFAULT 0,
+    create_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP,
+    update_time TIMESTAMP        DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS share_flag_account_id_index ON share_flag (account_id);
+INSERT INTO config (key, value, remark)
+SELECT 'SUBSCRIBE_LOG_DAYS', '30', 'Subscription Access Log Retention Days'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SUBSCRIBE_LOG_DAYS');
+INSERT INTO config (key, value, remark)
+SELECT 'SHARE_DETECT_ENABLE', '0', 'Sharing Detection Enable'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SHARE_DETECT_ENABLE');
+INSERT INTO config (key, value, remark)
+SELECT 'SHARE_DETECT_MAX_IPS', '5', 'Sharing Detection Max Distinct IPs'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SHARE_DETECT_MAX_IPS');
+INSERT INTO config (key, value, remark)
+SELECT 'SHARE_DETECT_WINDOW', '60', 'Sharing Detection Window Minutes'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SHARE_DETECT_WINDOW');
+INSERT INTO config (key, value, remark)
+SELECT 'SHARE_DETECT_NETWORK', 'ip', 'Sharing Detection Counting Unit'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SHARE_DETECT_NETWORK');
+INSERT INTO config (key, value, remark)
+SELECT 'SHARE_DETECT_ACTION', 'notify', 'Sharing Detection Action'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SHARE_DETECT_ACTION');
+INSERT INTO config (key, value, remark)
+SELECT 'SHARE_DETECT_KICK_MINUTES', '10', 'Sharing Detection Kick Minutes'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SHARE_DETECT_KICK_MINUTES');
+INSERT INTO config (key, value, remark)
+SELECT 'HYSTERIA2_CLIENT_SOCKS5', '127.0.0.1:1080', 'Hysteria2 Client SOCKS5 Listen'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HYSTERIA2_CLIENT_SOCKS5');
+INSERT INTO config (key, value, remark)
+SELECT 'HYSTERIA2_CLIENT_HTTP', '127.0.0.1:8080', 'Hysteria2 Client HTTP Listen'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HYSTERIA2_CLIENT_HTTP');
+INSERT INTO config (key, value, remark)
+SELECT 'CERT_EXPIRE_WARN_DAYS', '14', 'Certificate Expiry Warning Days'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'CERT_EXPIRE_WARN_DAYS');
+INSERT INTO config (key, value, remark)
+SELECT 'HUI_CERT_FROM_HYSTERIA2', '0', 'H UI Use Hysteria2 Certificate'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HUI_CERT_FROM_HYSTERIA2');
+INSERT INTO config (key, value, remark)
+SELECT 'HUI_REAL_IP_HEADER', 'X-Forwarded-For', 'H UI Real IP Header'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HUI_REAL_IP_HEADER');
+INSERT INTO config (key, value, remark)
+SELECT 'HUI_BIND_ADDRESS', '', 'H UI Bind Address'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HUI_BIND_ADDRESS');
+INSERT INTO config (key, value, remark)
+SELECT 'HUI_UNIX_SOCKET', '', 'H UI Unix Socket Path'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HUI_UNIX_SOCKET');
+INSERT INTO config (key, value, remark)
+SELECT 'FILTER_USER_AGENTS', 'fofa,shodan,curl,wget', 'Blocked User-Agent Keywords'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'FILTER_USER_AGENTS');
+INSERT INTO config (key, value, remark)
+SELECT 'SUBSCRIBE_FILTER_USER_AGENTS', 'fofa,shodan', 'Subscription Blocked User-Agent Keywords'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'SUBSCRIBE_FILTER_USER_AGENTS');
+INSERT INTO config (key, value, remark)
+SELECT 'ADMIN_ALLOW_IPS', '', 'Admin Allowed IPs'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'ADMIN_ALLOW_IPS');
+INSERT INTO config (key, value, remark)
+SELECT 'ADMIN_DENY_IPS', '', 'Admin Denied IPs'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'ADMIN_DENY_IPS');
+INSERT INTO config (key, value, remark)
+SELECT 'ADMIN_ALLOW_COUNTRIES', '', 'Admin Allowed Countries'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'ADMIN_ALLOW_COUNTRIES');
+INSERT INTO config (key, value, remark)
+SELECT 'ADMIN_DENY_COUNTRIES', '', 'Admin Denied Countries'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'ADMIN_DENY_COUNTRIES');
+INSERT INTO config (key, value, remark)
+SELECT 'PUBLIC_ALLOW_IPS', '', 'Public Allowed IPs'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'PUBLIC_ALLOW_IPS');
+INSERT INTO config (key, value, remark)
+SELECT 'PUBLIC_DENY_IPS', '', 'Public Denied IPs'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'PUBLIC_DENY_IPS');
+INSERT INTO config (key, value, remark)
+SELECT 'PUBLIC_ALLOW_COUNTRIES', '', 'Public Allowed Countries'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'PUBLIC_ALLOW_COUNTRIES');
+INSERT INTO config (key, value, remark)
+SELECT 'PUBLIC_DENY_COUNTRIES', '', 'Public Denied Countries'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'PUBLIC_DENY_COUNTRIES');
+INSERT INTO config (key, value, remark)
+SELECT 'GEOIP_DB_PATH', '', 'GeoIP MMDB File Path'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'GEOIP_DB_PATH');
+INSERT INTO config (key, value, remark)
+SELECT 'HUI_UNIX_SOCKET_MODE', '0660', 'H UI Unix Socket File Mode'
+    WHERE NOT EXISTS (SELECT 1 FROM config WHERE key = 'HUI_UNIX_SOCKET_MODE');
\ No newline at end of file
@@ -5,6 +5,8 @@ import {
   AccountInfo,
   AccountLoginDto,
   AccountLoginVo,
+  LoginStatusDto,
+  LoginStatusVo,
   AccountPageDto,
   AccountUpdateDto,
   AccountVo,
@@ -107,6 +109,20 @@ export function loginApi(data: AccountLoginDto): AxiosPromise<AccountLoginVo> {
   });
 }
 
+/**
+ * 查询 Telegram 2FA 审批结果，服务端最多等待 25 秒后返回
+ * @param data
+ */
+export function loginStatusApi(
+  data: LoginStatusDto
+): AxiosPromise<LoginStatusVo> {
+  return request({
+    url: "/auth/loginStatus",
+    method: "get",
+    params: data,
+  });
+}
+
 /**
  * 导入
  */
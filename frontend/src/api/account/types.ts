@@ -50,6 +50,17 @@ export interface AccountVo extends IdDto {
 export interface AccountLoginVo {
   accessToken: string;
   tokenType: string;
+  pendingId?: string; // 开启 Telegram 2FA 时等待审批
+}
+
+export interface LoginStatusDto {
+  pendingId: string;
+}
+
+export interface LoginStatusVo {
+  status: string; // pending approved denied expired
+  tokenType?: string;
+  accessToken?: string;
 }
 
 export interface AccountInfo {
@@ -2,6 +2,7 @@ import { AxiosPromise } from "axios";
 import { Hysteria2ServerConfig } from "@/api/config/types";
 import request from "@/utils/request";
 import {
+  Hysteria2ClientConfigDto,
   Hysteria2KickDto,
   Hysteria2SubscribeVo,
   Hysteria2SubscribeUrlDto,
@@ -56,3 +57,14 @@ export function hysteria2UrlApi(
     params: dto,
   });
 }
+
+export function hysteria2ClientConfigApi(
+  data: Hysteria2ClientConfigDto
+): AxiosPromise {
+  return request({
+    url: "/hysteria2/hysteria2ClientConfig",
+    method: "post",
+    data: data,
+    responseType: "blob",
+  });
+}
@@ -13,6 +13,11 @@ export interface Hysteria2SubscribeUrlDto {
   host: string;
 }
 
+export interface Hysteria2ClientConfigDto {
+  accountId: number;
+  hostname: string;
+}
+
 export interface Hysteria2UrlDto {
   accountId: number;
   hostname: string;
@@ -23,6 +23,10 @@ export default {
     username: "Username",
     password: "Password",
     login: "Login",
+    waitingApproval: "Waiting for approval in Telegram...",
+    cancel: "Cancel",
+    denied: "Login was denied in Telegram",
+    expired: "Login approval expired, please login again",
   },
   // 导航栏国际化
   navbar: {
@@ -48,6 +52,7 @@ export default {
     subscribeQrCode: "Subscribe QR Code",
     nodeUrl: "Node URL",
     nodeQrCode: "Node QR Code",
+    clientConfig: "Client Config",
     resetTraffic: "Reset traffic",
     import: "Import",
     export: "Export",
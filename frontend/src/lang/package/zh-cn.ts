@@ -23,6 +23,10 @@ export default {
     username: "用户名",
     password: "密码",
     login: "登 录",
+    waitingApproval: "等待 Telegram 审批...",
+    cancel: "取 消",
+    denied: "登录已在 Telegram 中被拒绝",
+    expired: "登录审批已过期，请重新登录",
   },
   // 导航栏国际化
   navbar: {
@@ -48,6 +52,7 @@ export default {
     subscribeQrCode: "订阅二维码",
     nodeUrl: "节点 URL",
     nodeQrCode: "节点二维码",
+    clientConfig: "客户端配置",
     resetTraffic: "重设流量",
     import: "导入",
     export: "导出",
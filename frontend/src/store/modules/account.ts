@@ -1,6 +1,6 @@
 import { defineStore } from "pinia";
 
-import { getAccountInfoApi, loginApi } from "@/api/account";
+import { getAccountInfoApi, loginApi, loginStatusApi } from "@/api/account";
 import { resetRouter } from "@/router";
 import { store } from "@/store";
 
@@ -14,6 +14,7 @@ export const useAccountStore = defineStore("account", () => {
   const id = ref(0);
   const username = ref("");
   const roles = ref<Array<string>>([]); // 用户角色编码集合 → 判断路由权限
+  const pendingId = ref(""); // 等待 Telegram 审批的登录请求，为空表示没有等待
 
   /**
    * 登录
@@ -25,6 +26,13 @@ export const useAccountStore = defineStore("account", () => {
       loginApi(accountLoginDto)
         .then((response) => {
           const { tokenType, accessToken } = response.data;
+          if (response.data.pendingId) {
+            pendingId.value = response.data.pendingId;
+            waitLoginApproval(response.data.pendingId)
+              .then(resolve)
+              .catch(reject);
+            return;
+          }
           token.value = tokenType + " " + accessToken; // Bearer eyJhbGciOiJIUzI1NiJ9.xxx.xxx
           resolve();
         })
@@ -34,6 +42,37 @@ export const useAccountStore = defineStore("account", () => {
     });
   }
 
+  /**
+   * 轮询 Telegram 审批结果，通过后保存 token，拒绝、过期或取消时返回错误
+   */
+  async function waitLoginApproval(id: string) {
+    try {
+      while (pendingId.value === id) {
+        const { data } = await loginStatusApi({ pendingId: id });
+        if (pendingId.value !== id) {
+          break;
+        }
+        if (data.status === "approved") {
+          token.value = data.tokenType + " " + data.accessToken;
+          return;
+        }
+        if (data.status !== "pending") {
+          throw new Error(data.status);
+        }
+      }
+      throw new Error("canceled");
+    } finally {
+      if (pendingId.value === id) {
+        pendingId.value = "";
+      }
+    }
+  }
+
+  // 取消等待审批
+  function cancelLogin() {
+    pendingId.value = "";
+  }
+
   // 查询当前
   function getAccountInfo() {
     return new Promise<AccountInfo>((resolve, reject) => {
@@ -78,7 +117,9 @@ export const useAccountStore = defineStore("account", () => {
     id,
     username,
     roles,
+    pendingId,
     login,
+    cancelLogin,
     getAccountInfo,
     logout,
     resetToken,
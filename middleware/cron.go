@@ -18,6 +18,51 @@ func InitCron() error {
 		logrus.Errorf("cron add func CronHandleAccount err: %v", err)
 		return errors.New("cron add func CronHandleAccount err")
 	}
+	_, err = c.AddFunc("@every 1m", service.CronSystemMetric)
+	if err != nil {
+		logrus.Errorf("cron add func CronSystemMetric err: %v", err)
+		return errors.New("cron add func CronSystemMetric err")
+	}
+	_, err = c.AddFunc("@every 15s", service.CronMetricsCpu)
+	if err != nil {
+		logrus.Errorf("cron add func CronMetricsCpu err: %v", err)
+		return errors.New("cron add func CronMetricsCpu err")
+	}
+	_, err = c.AddFunc("@every 1m", service.CronAlert)
+	if err != nil {
+		logrus.Errorf("cron add func CronAlert err: %v", err)
+		return errors.New("cron add func CronAlert err")
+	}
+	_, err = c.AddFunc("@every 1m", service.CronAccountEvent)
+	if err != nil {
+		logrus.Errorf("cron add func CronAccountEvent err: %v", err)
+		return errors.New("cron add func CronAccountEvent err")
+	}
+	_, err = c.AddFunc("@every 1m", service.CronServerCert)
+	if err != nil {
+		logrus.Errorf("cron add func CronServerCert err: %v", err)
+		return errors.New("cron add func CronServerCert err")
+	}
+	_, err = c.AddFunc("@every 1h", service.CronCertCheck)
+	if err != nil {
+		logrus.Errorf("cron add func CronCertCheck err: %v", err)
+		return errors.New("cron add func CronCertCheck err")
+	}
+	_, err = c.AddFunc("@every 5m", service.CronShareDetect)
+	if err != nil {
+		logrus.Errorf("cron add func CronShareDetect err: %v", err)
+		return errors.New("cron add func CronShareDetect err")
+	}
+	_, err = c.AddFunc("@every 5m", service.CronTelegramUserWarn)
+	if err != nil {
+		logrus.Errorf("cron add func CronTelegramUserWarn err: %v", err)
+		return errors.New("cron add func CronTelegramUserWarn err")
+	}
+	_, err = c.AddFunc("@every 1m", service.CronWebhookRetry)
+	if err != nil {
+		logrus.Errorf("cron add func CronWebhookRetry err: %v", err)
+		return errors.New("cron add func CronWebhookRetry err")
+	}
 	resetTrafficCron, err := dao.GetConfig("key = ?", constant.ResetTrafficCron)
 	if err != nil {
 		return err
@@ -28,6 +73,26 @@ func InitCron() error {
 			logrus.Errorf("cron add func CronResetTraffic err: %v", err)
 		}
 	}
+	reportCrons, err := dao.ListConfig("key in ?", []string{
+		constant.TelegramReportDailyCron,
+		constant.TelegramReportWeeklyCron,
+		constant.TelegramReportMonthlyCron})
+	if err != nil {
+		return err
+	}
+	reportJobs := map[string]func(){
+		constant.TelegramReportDailyCron:   service.CronTelegramReportDaily,
+		constant.TelegramReportWeeklyCron:  service.CronTelegramReportWeekly,
+		constant.TelegramReportMonthlyCron: service.CronTelegramReportMonthly,
+	}
+	for _, item := range reportCrons {
+		if item.Value == nil || *item.Value == "" {
+			continue
+		}
+		if _, err := c.AddFunc(*item.Value, reportJobs[*item.Key]); err != nil {
+			logrus.Errorf("cron add func %s err: %v", *item.Key, err)
+		}
+	}
 	c.Start()
 	return nil
 }
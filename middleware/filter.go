@@ -2,21 +2,38 @@ package middleware
 
 import (
 	"github.com/gin-gonic/gin"
+	"h-ui/model/constant"
 	"h-ui/model/vo"
+	"h-ui/service"
 	"net/http"
-	"regexp"
 )
 
 func FilterHandler() gin.HandlerFunc {
+	return filterUserAgent(constant.FilterUserAgents)
+}
+
+// SubscribeFilterHandler 订阅使用单独的关键字，默认允许 curl 和 wget，方便用户在脚本中拉取
+func SubscribeFilterHandler() gin.HandlerFunc {
+	return filterUserAgent(constant.SubscribeFilterUserAgents)
+}
+
+// filterUserAgent 每次请求读取 key 配置的关键字，修改后立即生效
+func filterUserAgent(key string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		matched, err := regexp.MatchString(`(?i)fofa|shodan|curl|wget`, c.Request.UserAgent())
-		if err != nil {
-			vo.Fail("Internal error", c)
-			c.AbortWithStatus(http.StatusInternalServerError)
+		if service.UserAgentBlocked(key, c.Request.UserAgent()) {
+			vo.Fail("Forbidden: Scanning tools are not allowed", c)
+			c.AbortWithStatus(http.StatusForbidden)
 			return
 		}
-		if matched {
-			vo.Fail("Forbidden: Scanning tools are not allowed", c)
+		c.Next()
+	}
+}
+
+// IPFilterHandler 按 IP 和国家名单限制访问，scope 为 admin 或 public
+func IPFilterHandler(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !service.IPFilterAllow(scope, c.ClientIP()) {
+			vo.Fail("Forbidden: your IP is not allowed", c)
 			c.AbortWithStatus(http.StatusForbidden)
 			return
 		}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"h-ui/service"
+	"time"
+)
+
+func MetricsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		startTime := time.Now()
+		c.Next()
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		service.MetricsHttp(c.Request.Method, route, time.Since(startTime).Seconds())
+	}
+}
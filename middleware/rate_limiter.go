@@ -15,7 +15,8 @@ func init() {
 
 func RateLimiterHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		httpError := tollbooth.LimitByRequest(limit, c.Writer, c.Request)
+		// 按 gin 解析的客户端 IP 限流，经过可信代理时不会把所有请求算作代理的 IP
+		httpError := tollbooth.LimitByKeys(limit, []string{c.ClientIP()})
 		if httpError != nil {
 			vo.Fail("click too fast", c)
 			c.Abort()
@@ -0,0 +1,20 @@
+package bo
+
+type DiskUsage struct {
+	Mountpoint string  `json:"mountpoint"`
+	Total      uint64  `json:"total"`
+	Used       uint64  `json:"used"`
+	Percent    float64 `json:"percent"`
+}
+
+type NetCounter struct {
+	Name      string `json:"name"`
+	BytesSent uint64 `json:"bytesSent"`
+	BytesRecv uint64 `json:"bytesRecv"`
+}
+
+type NetRate struct {
+	Name     string  `json:"name"`
+	SentRate float64 `json:"sentRate"` // bytes/s
+	RecvRate float64 `json:"recvRate"` // bytes/s
+}
@@ -13,6 +13,7 @@ type Hysteria2 struct {
 	ObfsPassword   string `yaml:"obfs-password,omitempty"`
 	Sni            string `yaml:"sni,omitempty"`
 	SkipCertVerify bool   `yaml:"skip-cert-verify,omitempty"`
+	Fingerprint    string `yaml:"fingerprint,omitempty"`
 }
 
 type ProxyGroup struct {
@@ -25,3 +26,133 @@ type ClashConfig struct {
 	Proxies     []interface{} `yaml:"proxies"`
 	ProxyGroups []ProxyGroup  `yaml:"proxy-groups"`
 }
+
+// Hysteria2Node 订阅中的一个节点，各客户端格式都由它生成
+type Hysteria2Node struct {
+	Name         string
+	Server       string
+	Port         string
+	Ports        string // 端口跳跃，逗号分隔，例如 20000-30000,40000
+	Password     string
+	Up           string
+	Down         string
+	ObfsPassword string
+	Sni          string
+	Insecure     bool
+	PinSHA256    string // 证书指纹，自签证书时使用
+}
+
+type SingBoxConfig struct {
+	Log       SingBoxLog        `json:"log"`
+	Inbounds  []SingBoxInbound  `json:"inbounds"`
+	Outbounds []SingBoxOutbound `json:"outbounds"`
+	Route     SingBoxRoute      `json:"route"`
+}
+
+type SingBoxLog struct {
+	Level     string `json:"level"`
+	Timestamp bool   `json:"timestamp"`
+}
+
+type SingBoxInbound struct {
+	Type        string   `json:"type"`
+	Tag         string   `json:"tag"`
+	Address     []string `json:"address,omitempty"`
+	AutoRoute   bool     `json:"auto_route,omitempty"`
+	StrictRoute bool     `json:"strict_route,omitempty"`
+	Listen      string   `json:"listen,omitempty"`
+	ListenPort  int64    `json:"listen_port,omitempty"`
+}
+
+type SingBoxOutbound struct {
+	Type        string       `json:"type"`
+	Tag         string       `json:"tag"`
+	Outbounds   []string     `json:"outbounds,omitempty"`
+	Server      string       `json:"server,omitempty"`
+	ServerPort  int64        `json:"server_port,omitempty"`
+	ServerPorts []string     `json:"server_ports,omitempty"`
+	UpMbps      int64        `json:"up_mbps,omitempty"`
+	DownMbps    int64        `json:"down_mbps,omitempty"`
+	Obfs        *SingBoxObfs `json:"obfs,omitempty"`
+	Password    string       `json:"password,omitempty"`
+	Tls         *SingBoxTls  `json:"tls,omitempty"`
+}
+
+type SingBoxObfs struct {
+	Type     string `json:"type"`
+	Password string `json:"password"`
+}
+
+type SingBoxTls struct {
+	Enabled    bool   `json:"enabled"`
+	ServerName string `json:"server_name,omitempty"`
+	Insecure   bool   `json:"insecure"`
+}
+
+type SingBoxRoute struct {
+	AutoDetectInterface bool   `json:"auto_detect_interface"`
+	Final               string `json:"final"`
+}
+
+type StashHysteria2 struct {
+	Name           string `yaml:"name"`
+	Type           string `yaml:"type"`
+	Server         string `yaml:"server"`
+	Port           string `yaml:"port"`
+	Auth           string `yaml:"auth"`
+	UpSpeed        int64  `yaml:"up-speed,omitempty"`
+	DownSpeed      int64  `yaml:"down-speed,omitempty"`
+	Obfs           string `yaml:"obfs,omitempty"`
+	ObfsPassword   string `yaml:"obfs-password,omitempty"`
+	Sni            string `yaml:"sni,omitempty"`
+	SkipCertVerify bool   `yaml:"skip-cert-verify"`
+}
+
+// Hysteria2ClientConfig 官方 hysteria 客户端配置
+type Hysteria2ClientConfig struct {
+	Server    string                    `yaml:"server"`
+	Auth      string                    `yaml:"auth"`
+	Obfs      *Hysteria2ClientObfs      `yaml:"obfs,omitempty"`
+	TLS       Hysteria2ClientTLS        `yaml:"tls"`
+	Bandwidth *Hysteria2ClientBandwidth `yaml:"bandwidth,omitempty"`
+	Socks5    *Hysteria2ClientListen    `yaml:"socks5,omitempty"`
+	HTTP      *Hysteria2ClientListen    `yaml:"http,omitempty"`
+}
+
+type Hysteria2ClientObfs struct {
+	Type       string                        `yaml:"type"`
+	Salamander Hysteria2ClientObfsSalamander `yaml:"salamander"`
+}
+
+type Hysteria2ClientObfsSalamander struct {
+	Password string `yaml:"password"`
+}
+
+type Hysteria2ClientTLS struct {
+	Sni       string `yaml:"sni,omitempty"`
+	Insecure  bool   `yaml:"insecure"`
+	PinSHA256 string `yaml:"pinSHA256,omitempty"`
+}
+
+type Hysteria2ClientBandwidth struct {
+	Up   string `yaml:"up,omitempty"`
+	Down string `yaml:"down,omitempty"`
+}
+
+type Hysteria2ClientListen struct {
+	Listen string `yaml:"listen"`
+}
+
+// SubscribeUaRule User-Agent 匹配 Pattern 时使用 Format 生成订阅
+type SubscribeUaRule struct {
+	Pattern string `json:"pattern"`
+	Format  string `json:"format"`
+}
+
+// SubscribeLogStat 订阅访问记录的分组统计
+type SubscribeLogStat struct {
+	Value   string `gorm:"column:value"`
+	Count   int64  `gorm:"column:count"`
+	FirstAt int64  `gorm:"column:first_at"`
+	LastAt  int64  `gorm:"column:last_at"`
+}
@@ -0,0 +1,13 @@
+package constant
+
+const (
+	AlertTypeCpu           = "cpu"
+	AlertTypeMem           = "mem"
+	AlertTypeDisk          = "disk"
+	AlertTypeHysteria2Down = "hysteria2_down"
+	AlertTypeOnlineDevices = "online_devices"
+	AlertTypeTrafficStats  = "traffic_stats"
+
+	AlertStateFiring   = "firing"
+	AlertStateResolved = "resolved"
+)
@@ -1,8 +1,17 @@
 package constant
 
+// 订阅格式，可以通过 ?format= 指定
 const (
 	Shadowrocket = "shadowrocket"
 	Clash        = "clash"
 	V2rayN       = "v2rayn"
 	NekoBox      = "nekobox"
+	SingBox      = "sing-box"
+	Surge        = "surge"
+	Stash        = "stash"
+	Loon         = "loon"
+	QuantumultX  = "quantumultx"
+	Hysteria2    = "hysteria2" // 官方客户端配置
 )
+
+var SubscribeFormats = []string{Shadowrocket, Clash, V2rayN, NekoBox, SingBox, Surge, Stash, Loon, QuantumultX, Hysteria2}
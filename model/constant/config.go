@@ -17,8 +17,68 @@ const (
 	TelegramChatId             = "TELEGRAM_CHAT_ID"
 	TelegramDebug              = "TELEGRAM_DEBUG"
 	TelegramDNSServers         = "TELEGRAM_DNS_SERVERS"
+	TelegramProxy              = "TELEGRAM_PROXY"
+	TelegramApiEndpoint        = "TELEGRAM_API_ENDPOINT"
 	TelegramLoginJobEnable     = "TELEGRAM_LOGIN_JOB_ENABLE"
 	TelegramLoginJobText       = "TELEGRAM_LOGIN_JOB_TEXT"
 	Telegram2FAEnable          = "TELEGRAM_2FA_ENABLE"
+	TelegramReportDailyCron    = "TELEGRAM_REPORT_DAILY_CRON"
+	TelegramReportWeeklyCron   = "TELEGRAM_REPORT_WEEKLY_CRON"
+	TelegramReportMonthlyCron  = "TELEGRAM_REPORT_MONTHLY_CRON"
+	TelegramReportTopN         = "TELEGRAM_REPORT_TOP_N"
+	TelegramReportExpireDays   = "TELEGRAM_REPORT_EXPIRE_DAYS"
+	TelegramReportText         = "TELEGRAM_REPORT_TEXT"
+	TelegramUserEnable         = "TELEGRAM_USER_ENABLE"
+	TelegramUserQuotaWarn      = "TELEGRAM_USER_QUOTA_WARN"
+	TelegramUserExpireDays     = "TELEGRAM_USER_EXPIRE_DAYS"
+	NotifyRoutes               = "NOTIFY_ROUTES"
+	NotifyWebhookUrl           = "NOTIFY_WEBHOOK_URL"
+	NotifyWebhookSecret        = "NOTIFY_WEBHOOK_SECRET"
+	NotifyWebhookRetry         = "NOTIFY_WEBHOOK_RETRY"
+	NotifySmtpHost             = "NOTIFY_SMTP_HOST"
+	NotifySmtpPort             = "NOTIFY_SMTP_PORT"
+	NotifySmtpUsername         = "NOTIFY_SMTP_USERNAME"
+	NotifySmtpPassword         = "NOTIFY_SMTP_PASSWORD"
+	NotifySmtpFrom             = "NOTIFY_SMTP_FROM"
+	NotifySmtpTo               = "NOTIFY_SMTP_TO"
 	ClashExtension             = "CLASH_EXTENSION"
+	SubscribeUaRules           = "SUBSCRIBE_UA_RULES"
+	SubscribeFormatDisabled    = "SUBSCRIBE_FORMAT_DISABLED"
+	SubscribeInfoEnable        = "SUBSCRIBE_INFO_ENABLE"
+	SubscribeInfoTemplate      = "SUBSCRIBE_INFO_TEMPLATE"
+	SubscribeAnnouncement      = "SUBSCRIBE_ANNOUNCEMENT"
+	SubscribeNoticeTemplate    = "SUBSCRIBE_NOTICE_TEMPLATE"
+	SubscribeServer            = "SUBSCRIBE_SERVER"
+	SubscribePort              = "SUBSCRIBE_PORT"
+	CertExpireWarnDays         = "CERT_EXPIRE_WARN_DAYS"
+	HUICertFromHysteria2       = "HUI_CERT_FROM_HYSTERIA2"
+	Hysteria2ClientSocks5      = "HYSTERIA2_CLIENT_SOCKS5"
+	Hysteria2ClientHttp        = "HYSTERIA2_CLIENT_HTTP"
+	SubscribeLogDays           = "SUBSCRIBE_LOG_DAYS"
+	ShareDetectEnable          = "SHARE_DETECT_ENABLE"
+	ShareDetectMaxIps          = "SHARE_DETECT_MAX_IPS"
+	ShareDetectWindow          = "SHARE_DETECT_WINDOW"
+	ShareDetectNetwork         = "SHARE_DETECT_NETWORK"
+	ShareDetectAction          = "SHARE_DETECT_ACTION"
+	ShareDetectKickMinutes     = "SHARE_DETECT_KICK_MINUTES"
+	HUIPublicUrl               = "HUI_PUBLIC_URL"
+	HUITrustedProxies          = "HUI_TRUSTED_PROXIES"
+	HUIRealIpHeader            = "HUI_REAL_IP_HEADER"
+	HUIBindAddress             = "HUI_BIND_ADDRESS"
+	HUIUnixSocket              = "HUI_UNIX_SOCKET"
+	HUIUnixSocketMode          = "HUI_UNIX_SOCKET_MODE"
+	FilterUserAgents           = "FILTER_USER_AGENTS"
+	SubscribeFilterUserAgents  = "SUBSCRIBE_FILTER_USER_AGENTS"
+	AdminAllowIps              = "ADMIN_ALLOW_IPS"
+	AdminDenyIps               = "ADMIN_DENY_IPS"
+	AdminAllowCountries        = "ADMIN_ALLOW_COUNTRIES"
+	AdminDenyCountries         = "ADMIN_DENY_COUNTRIES"
+	PublicAllowIps             = "PUBLIC_ALLOW_IPS"
+	PublicDenyIps              = "PUBLIC_DENY_IPS"
+	PublicAllowCountries       = "PUBLIC_ALLOW_COUNTRIES"
+	PublicDenyCountries        = "PUBLIC_DENY_COUNTRIES"
+	GeoIPDBPath                = "GEOIP_DB_PATH"
+	MetricsEnable              = "METRICS_ENABLE"
+	MetricsToken               = "METRICS_TOKEN"
+	MetricsAllowIps            = "METRICS_ALLOW_IPS"
 )
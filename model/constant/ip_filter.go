@@ -0,0 +1,7 @@
+package constant
+
+// IP 过滤的范围，admin 为管理接口，public 为订阅、登录和 hysteria2 认证接口
+const (
+	IPFilterAdmin  = "admin"
+	IPFilterPublic = "public"
+)
@@ -0,0 +1,10 @@
+package constant
+
+const (
+	LoginSuccess  = "success"
+	LoginFailed   = "failed"
+	LoginPending  = "pending"
+	LoginApproved = "approved"
+	LoginDenied   = "denied"
+	LoginExpired  = "expired"
+)
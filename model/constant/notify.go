@@ -0,0 +1,16 @@
+package constant
+
+const (
+	NotifyEventLogin          = "login"
+	NotifyEventAlert          = "alert"
+	NotifyEventQuota          = "quota"
+	NotifyEventExpiry         = "expiry"
+	NotifyEventHysteria2Crash = "hysteria2_crash"
+	NotifyEventReport         = "report"
+	NotifyEventSharing        = "sharing"
+	NotifyEventCert           = "cert"
+
+	NotifyChannelTelegram = "telegram"
+	NotifyChannelWebhook  = "webhook"
+	NotifyChannelSmtp     = "smtp"
+)
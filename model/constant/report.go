@@ -0,0 +1,7 @@
+package constant
+
+const (
+	ReportPeriodDaily   = "daily"
+	ReportPeriodWeekly  = "weekly"
+	ReportPeriodMonthly = "monthly"
+)
@@ -0,0 +1,21 @@
+package constant
+
+// 订阅访问记录来源
+const (
+	SubscribeLogSourceSubscribe = "subscribe"
+	SubscribeLogSourceAuth      = "auth"
+)
+
+// 共享检测按单个 IP 或按网段（IPv4 /24、IPv6 /64）计数
+const (
+	ShareNetworkIp      = "ip"
+	ShareNetworkNetwork = "network"
+)
+
+// 共享检测命中后的操作
+const (
+	ShareActionNone   = ""
+	ShareActionNotify = "notify"
+	ShareActionRotate = "rotate"
+	ShareActionKick   = "kick"
+)
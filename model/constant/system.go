@@ -5,11 +5,17 @@ const (
 	SqliteDBDir   = "data/"
 	BinDir        = "bin/"
 	ExportPathDir = "export/"
+	CertDir       = "cert/"
 
 	SqliteDBPath = "data/h_ui.db"
 
 	Hysteria2ConfigPath = "bin/hysteria2.yaml"
 
+	// 面板生成的自签证书
+	CertCaName        = "ca"
+	CertHysteria2Name = "hysteria2"
+	CertPanelName     = "panel"
+
 	SystemLogPath    = "logs/h-ui.log"
 	Hysteria2LogPath = "logs/hysteria2.log"
 
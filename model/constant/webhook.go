@@ -0,0 +1,27 @@
+package constant
+
+const (
+	AccountEventCreated         = "account.created"
+	AccountEventUpdated         = "account.updated"
+	AccountEventDeleted         = "account.deleted"
+	AccountEventQuotaExceeded   = "account.quota_exceeded"
+	AccountEventExpired         = "account.expired"
+	AccountEventKicked          = "account.kicked"
+	AccountEventTrafficReset    = "account.traffic_reset"
+	AccountEventFirstConnection = "account.first_connection"
+
+	WebhookDeliveryPending = "pending"
+	WebhookDeliverySuccess = "success"
+	WebhookDeliveryFailed  = "failed"
+)
+
+var AccountEvents = []string{
+	AccountEventCreated,
+	AccountEventUpdated,
+	AccountEventDeleted,
+	AccountEventQuotaExceeded,
+	AccountEventExpired,
+	AccountEventKicked,
+	AccountEventTrafficReset,
+	AccountEventFirstConnection,
+}
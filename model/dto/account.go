@@ -31,3 +31,13 @@ type AccountUpdateDto struct {
 	DeviceNo   *int64  `json:"deviceNo" form:"deviceNo" validate:"omitempty,min=1"`
 	Deleted    *int64  `json:"deleted" form:"deleted" validate:"omitempty,oneof=0 1"`
 }
+
+type LoginStatusDto struct {
+	PendingId *string `json:"pendingId" form:"pendingId" validate:"required,len=32,alphanum"`
+}
+
+type LoginHistoryPageDto struct {
+	BaseDto
+	Username *string `json:"username" form:"username" validate:"omitempty,min=1,max=32"`
+	Status   *string `json:"status" form:"status" validate:"omitempty,oneof=success failed approved denied expired"`
+}
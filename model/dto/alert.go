@@ -0,0 +1,26 @@
+package dto
+
+type AlertRuleSaveDto struct {
+	Name      *string  `json:"name" form:"name" validate:"required,min=1,max=64"`
+	Type      *string  `json:"type" form:"type" validate:"required,oneof=cpu mem disk hysteria2_down online_devices traffic_stats"`
+	Threshold *float64 `json:"threshold" form:"threshold" validate:"required,min=0"`
+	Duration  *int64   `json:"duration" form:"duration" validate:"required,min=0,max=1440"`
+	Cooldown  *int64   `json:"cooldown" form:"cooldown" validate:"required,min=0,max=10080"`
+	Enable    *int64   `json:"enable" form:"enable" validate:"required,oneof=0 1"`
+}
+
+type AlertRuleUpdateDto struct {
+	IdDto
+	Name      *string  `json:"name" form:"name" validate:"omitempty,min=1,max=64"`
+	Type      *string  `json:"type" form:"type" validate:"omitempty,oneof=cpu mem disk hysteria2_down online_devices traffic_stats"`
+	Threshold *float64 `json:"threshold" form:"threshold" validate:"omitempty,min=0"`
+	Duration  *int64   `json:"duration" form:"duration" validate:"omitempty,min=0,max=1440"`
+	Cooldown  *int64   `json:"cooldown" form:"cooldown" validate:"omitempty,min=0,max=10080"`
+	Enable    *int64   `json:"enable" form:"enable" validate:"omitempty,oneof=0 1"`
+}
+
+type AlertHistoryPageDto struct {
+	BaseDto
+	RuleId *int64  `json:"ruleId" form:"ruleId" validate:"omitempty,gt=0"`
+	State  *string `json:"state" form:"state" validate:"omitempty,oneof=firing resolved"`
+}
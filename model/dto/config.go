@@ -16,3 +16,14 @@ type ConfigUpdateDto struct {
 type ConfigsUpdateDto struct {
 	ConfigUpdateDtos []ConfigUpdateDto `json:"configUpdateDtos" form:"configUpdateDtos" validate:"required"`
 }
+
+type ManagedCertGenerateDto struct {
+	Target *string `json:"target" form:"target" validate:"required,oneof=hysteria2 panel"`
+	Hosts  *string `json:"hosts" form:"hosts" validate:"required,min=1,max=1024"` // 逗号分隔的域名或 IP
+	Days   *int64  `json:"days" form:"days" validate:"omitempty,gt=0,lte=3650"`   // 默认 3650 天
+	Ca     *int64  `json:"ca" form:"ca" validate:"omitempty,oneof=0 1"`           // 1 表示由面板 CA 签发
+}
+
+type ManagedCertRotateDto struct {
+	Target *string `json:"target" form:"target" validate:"required,oneof=hysteria2 panel"`
+}
@@ -25,3 +25,19 @@ type Hysteria2UrlDto struct {
 	AccountId *int64  `json:"accountId" form:"accountId" validate:"required,gt=0"`
 	Hostname  *string `json:"hostname" form:"hostname" validate:"required,min=1,max=255"`
 }
+
+type Hysteria2ClientConfigDto struct {
+	AccountId *int64  `json:"accountId" form:"accountId" validate:"required,gt=0"`
+	Hostname  *string `json:"hostname" form:"hostname" validate:"required,min=1,max=255"`
+	NodeId    *int64  `json:"nodeId" form:"nodeId" validate:"omitempty,gt=0"`   // 为空时使用本机节点
+	Socks5    *string `json:"socks5" form:"socks5" validate:"omitempty,max=64"` // 为空时使用 HYSTERIA2_CLIENT_SOCKS5
+	Http      *string `json:"http" form:"http" validate:"omitempty,max=64"`     // 为空时使用 HYSTERIA2_CLIENT_HTTP
+}
+
+type ClashPreviewDto struct {
+	AccountId *int64  `json:"accountId" form:"accountId" validate:"required,gt=0"`
+	Protocol  *string `json:"protocol" form:"protocol" validate:"required,min=1,max=8"`
+	Host      *string `json:"host" form:"host" validate:"required,min=1,max=301"`
+	Format    *string `json:"format" form:"format" validate:"omitempty,oneof=clash stash"`
+	Template  *string `json:"template" form:"template" validate:"omitempty"` // 为空时使用已保存的 CLASH_EXTENSION
+}
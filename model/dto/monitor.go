@@ -0,0 +1,5 @@
+package dto
+
+type SystemMetricDto struct {
+	Period *string `json:"period" form:"period" validate:"required,oneof=hour day week"`
+}
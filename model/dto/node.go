@@ -0,0 +1,24 @@
+package dto
+
+type NodeSaveDto struct {
+	Name         *string `json:"name" form:"name" validate:"required,min=1,max=64"`
+	Host         *string `json:"host" form:"host" validate:"required,min=1,max=255"`
+	Port         *int64  `json:"port" form:"port" validate:"required,min=1,max=65535"`
+	PortHopping  *string `json:"portHopping" form:"portHopping" validate:"omitempty,max=255"`
+	Sni          *string `json:"sni" form:"sni" validate:"omitempty,max=255"`
+	ObfsPassword *string `json:"obfsPassword" form:"obfsPassword" validate:"omitempty,max=255"`
+	Sort         *int64  `json:"sort" form:"sort" validate:"omitempty"`
+	Enable       *int64  `json:"enable" form:"enable" validate:"required,oneof=0 1"`
+}
+
+type NodeUpdateDto struct {
+	IdDto
+	Name         *string `json:"name" form:"name" validate:"omitempty,min=1,max=64"`
+	Host         *string `json:"host" form:"host" validate:"omitempty,min=1,max=255"`
+	Port         *int64  `json:"port" form:"port" validate:"omitempty,min=1,max=65535"`
+	PortHopping  *string `json:"portHopping" form:"portHopping" validate:"omitempty,max=255"`
+	Sni          *string `json:"sni" form:"sni" validate:"omitempty,max=255"`
+	ObfsPassword *string `json:"obfsPassword" form:"obfsPassword" validate:"omitempty,max=255"`
+	Sort         *int64  `json:"sort" form:"sort" validate:"omitempty"`
+	Enable       *int64  `json:"enable" form:"enable" validate:"omitempty,oneof=0 1"`
+}
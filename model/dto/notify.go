@@ -0,0 +1,5 @@
+package dto
+
+type NotifyTestDto struct {
+	Channel *string `json:"channel" form:"channel" validate:"required,oneof=telegram webhook smtp"`
+}
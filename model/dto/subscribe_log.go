@@ -0,0 +1,18 @@
+package dto
+
+type SubscribeLogPageDto struct {
+	BaseDto
+	AccountId *int64  `json:"accountId" form:"accountId" validate:"omitempty,gt=0"`
+	Source    *string `json:"source" form:"source" validate:"omitempty,oneof=subscribe auth"`
+	Ip        *string `json:"ip" form:"ip" validate:"omitempty,max=64"`
+}
+
+type SubscribeLogStatDto struct {
+	AccountId *int64 `json:"accountId" form:"accountId" validate:"required,gt=0"`
+	Days      *int64 `json:"days" form:"days" validate:"omitempty,gt=0,lte=365"` // 统计最近几天，默认 7 天
+}
+
+type ShareFlagPageDto struct {
+	BaseDto
+	AccountId *int64 `json:"accountId" form:"accountId" validate:"omitempty,gt=0"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+type WebhookSaveDto struct {
+	Name   *string `json:"name" form:"name" validate:"required,min=1,max=64"`
+	Url    *string `json:"url" form:"url" validate:"required,url,max=512"`
+	Secret *string `json:"secret" form:"secret" validate:"omitempty,max=128"`
+	Events *string `json:"events" form:"events" validate:"omitempty,max=512"` // 逗号分隔，为空表示全部事件
+	Enable *int64  `json:"enable" form:"enable" validate:"required,oneof=0 1"`
+}
+
+type WebhookUpdateDto struct {
+	IdDto
+	Name   *string `json:"name" form:"name" validate:"omitempty,min=1,max=64"`
+	Url    *string `json:"url" form:"url" validate:"omitempty,url,max=512"`
+	Secret *string `json:"secret" form:"secret" validate:"omitempty,max=128"`
+	Events *string `json:"events" form:"events" validate:"omitempty,max=512"`
+	Enable *int64  `json:"enable" form:"enable" validate:"omitempty,oneof=0 1"`
+}
+
+type WebhookDeliveryPageDto struct {
+	BaseDto
+	WebhookId *int64  `json:"webhookId" form:"webhookId" validate:"omitempty,gt=0"`
+	Event     *string `json:"event" form:"event" validate:"omitempty,max=64"`
+	Status    *string `json:"status" form:"status" validate:"omitempty,oneof=pending success failed"`
+}
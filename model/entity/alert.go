@@ -0,0 +1,23 @@
+package entity
+
+type AlertRule struct {
+	Name       *string  `gorm:"column:name;default:''" json:"name"`
+	Type       *string  `gorm:"column:type;default:''" json:"type"`
+	Threshold  *float64 `gorm:"column:threshold;default:0" json:"threshold"`
+	Duration   *int64   `gorm:"column:duration;default:0" json:"duration"` // minutes
+	Cooldown   *int64   `gorm:"column:cooldown;default:0" json:"cooldown"` // minutes
+	Enable     *int64   `gorm:"column:enable;default:1" json:"enable"`
+	BaseEntity `gorm:"embedded"`
+}
+
+type AlertHistory struct {
+	RuleId     *int64   `gorm:"column:rule_id;default:0" json:"ruleId"`
+	RuleName   *string  `gorm:"column:rule_name;default:''" json:"ruleName"`
+	Type       *string  `gorm:"column:type;default:''" json:"type"`
+	State      *string  `gorm:"column:state;default:''" json:"state"`
+	Value      *float64 `gorm:"column:value;default:0" json:"value"`
+	Message    *string  `gorm:"column:message;default:''" json:"message"`
+	Notified   *int64   `gorm:"column:notified;default:0" json:"notified"`
+	AlertAt    *int64   `gorm:"column:alert_at;default:0" json:"alertAt"`
+	BaseEntity `gorm:"embedded"`
+}
@@ -0,0 +1,9 @@
+package entity
+
+type LoginHistory struct {
+	Username   *string `gorm:"column:username;default:''" json:"username"`
+	Ip         *string `gorm:"column:ip;default:''" json:"ip"`
+	Status     *string `gorm:"column:status;default:''" json:"status"`
+	LoginAt    *int64  `gorm:"column:login_at;default:0" json:"loginAt"`
+	BaseEntity `gorm:"embedded"`
+}
@@ -0,0 +1,14 @@
+package entity
+
+// Node 其他服务器上的 hysteria2 节点，与本机共用账户
+type Node struct {
+	Name         *string `gorm:"column:name;default:''" json:"name"`
+	Host         *string `gorm:"column:host;default:''" json:"host"`
+	Port         *int64  `gorm:"column:port;default:443" json:"port"`
+	PortHopping  *string `gorm:"column:port_hopping;default:''" json:"portHopping"`
+	Sni          *string `gorm:"column:sni;default:''" json:"sni"`
+	ObfsPassword *string `gorm:"column:obfs_password;default:''" json:"obfsPassword"`
+	Sort         *int64  `gorm:"column:sort;default:0" json:"sort"`
+	Enable       *int64  `gorm:"column:enable;default:1" json:"enable"`
+	BaseEntity   `gorm:"embedded"`
+}
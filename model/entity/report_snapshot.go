@@ -0,0 +1,9 @@
+package entity
+
+type ReportSnapshot struct {
+	Period     *string `gorm:"column:period;default:''" json:"period"`
+	Traffic    *string `gorm:"column:traffic;default:''" json:"traffic"`
+	Restarts   *int64  `gorm:"column:restarts;default:0" json:"restarts"`
+	ReportAt   *int64  `gorm:"column:report_at;default:0" json:"reportAt"`
+	BaseEntity `gorm:"embedded"`
+}
@@ -0,0 +1,20 @@
+package entity
+
+type SubscribeLog struct {
+	AccountId  *int64  `gorm:"column:account_id;default:0" json:"accountId"`
+	Source     *string `gorm:"column:source;default:''" json:"source"`
+	Ip         *string `gorm:"column:ip;default:''" json:"ip"`
+	UserAgent  *string `gorm:"column:user_agent;default:''" json:"userAgent"`
+	Format     *string `gorm:"column:format;default:''" json:"format"`
+	AccessAt   *int64  `gorm:"column:access_at;default:0" json:"accessAt"`
+	BaseEntity `gorm:"embedded"`
+}
+
+type ShareFlag struct {
+	AccountId  *int64  `gorm:"column:account_id;default:0" json:"accountId"`
+	IpCount    *int64  `gorm:"column:ip_count;default:0" json:"ipCount"`
+	Ips        *string `gorm:"column:ips;default:''" json:"ips"`
+	Action     *string `gorm:"column:action;default:''" json:"action"`
+	FlagAt     *int64  `gorm:"column:flag_at;default:0" json:"flagAt"`
+	BaseEntity `gorm:"embedded"`
+}
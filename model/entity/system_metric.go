@@ -0,0 +1,12 @@
+package entity
+
+type SystemMetric struct {
+	CpuPercent  *float64 `gorm:"column:cpu_percent;default:0" json:"cpuPercent"`
+	MemPercent  *float64 `gorm:"column:mem_percent;default:0" json:"memPercent"`
+	Disks       *string  `gorm:"column:disks;default:''" json:"disks"`
+	Nets        *string  `gorm:"column:nets;default:''" json:"nets"`
+	UserTotal   *int64   `gorm:"column:user_total;default:0" json:"userTotal"`
+	DeviceTotal *int64   `gorm:"column:device_total;default:0" json:"deviceTotal"`
+	SampleTime  *int64   `gorm:"column:sample_time;default:0" json:"sampleTime"`
+	BaseEntity  `gorm:"embedded"`
+}
@@ -0,0 +1,9 @@
+package entity
+
+type TelegramBind struct {
+	AccountId    *int64 `gorm:"column:account_id;default:0" json:"accountId"`
+	ChatId       *int64 `gorm:"column:chat_id;default:0" json:"chatId"`
+	QuotaWarned  *int64 `gorm:"column:quota_warned;default:0" json:"quotaWarned"`
+	ExpireWarned *int64 `gorm:"column:expire_warned;default:0" json:"expireWarned"`
+	BaseEntity   `gorm:"embedded"`
+}
@@ -28,3 +28,22 @@ type AccountInfoVo struct {
 	Username string   `json:"username"`
 	Roles    []string `json:"roles"`
 }
+
+type LoginHistoryVo struct {
+	Id       int64  `json:"id"`
+	Username string `json:"username"`
+	Ip       string `json:"ip"`
+	Status   string `json:"status"`
+	LoginAt  int64  `json:"loginAt"`
+}
+
+type LoginHistoryPageVo struct {
+	LoginHistoryVos []LoginHistoryVo `json:"records"`
+	Total           int64            `json:"total"`
+}
+
+type TelegramBindCodeVo struct {
+	Code     string `json:"code"`
+	Link     string `json:"link"`
+	ExpireAt int64  `json:"expireAt"`
+}
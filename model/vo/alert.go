@@ -0,0 +1,29 @@
+package vo
+
+type AlertRuleVo struct {
+	BaseVo
+	Name      string  `json:"name"`
+	Type      string  `json:"type"`
+	Threshold float64 `json:"threshold"`
+	Duration  int64   `json:"duration"`
+	Cooldown  int64   `json:"cooldown"`
+	Enable    int64   `json:"enable"`
+	State     string  `json:"state"` // current state
+}
+
+type AlertHistoryVo struct {
+	Id       int64   `json:"id"`
+	RuleId   int64   `json:"ruleId"`
+	RuleName string  `json:"ruleName"`
+	Type     string  `json:"type"`
+	State    string  `json:"state"`
+	Value    float64 `json:"value"`
+	Message  string  `json:"message"`
+	Notified int64   `json:"notified"`
+	AlertAt  int64   `json:"alertAt"`
+}
+
+type AlertHistoryPageVo struct {
+	AlertHistoryVos []AlertHistoryVo `json:"records"`
+	Total           int64            `json:"total"`
+}
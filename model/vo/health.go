@@ -0,0 +1,18 @@
+package vo
+
+const (
+	HealthOk   = "ok"
+	HealthFail = "fail"
+	HealthSkip = "skip"
+)
+
+type HealthCheckVo struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+type ReadinessVo struct {
+	Status string          `json:"status"`
+	Checks []HealthCheckVo `json:"checks"`
+}
@@ -38,3 +38,33 @@ type Hysteria2AcmePathVo struct {
 	CrtPath string `json:"crtPath"`
 	KeyPath string `json:"keyPath"`
 }
+
+type ManagedCertVo struct {
+	Target    string   `json:"target"`
+	CrtPath   string   `json:"crtPath"`
+	KeyPath   string   `json:"keyPath"`
+	Hosts     []string `json:"hosts"`
+	CaCert    string   `json:"caCert"` // 由面板 CA 签发时为 CA 证书，客户端可以导入信任
+	PinSHA256 string   `json:"pinSHA256"`
+	NotAfter  int64    `json:"notAfter"`
+}
+
+type CertInfoVo struct {
+	Target         string   `json:"target"`
+	CrtPath        string   `json:"crtPath"`
+	KeyPath        string   `json:"keyPath"`
+	Subject        string   `json:"subject"`
+	Issuer         string   `json:"issuer"`
+	DnsNames       []string `json:"dnsNames"`
+	IpAddresses    []string `json:"ipAddresses"`
+	NotBefore      int64    `json:"notBefore"`
+	NotAfter       int64    `json:"notAfter"`
+	DaysLeft       int64    `json:"daysLeft"`
+	KeyMatch       bool     `json:"keyMatch"`
+	SelfSigned     bool     `json:"selfSigned"`
+	Managed        bool     `json:"managed"` // 由面板生成
+	PinSHA256      string   `json:"pinSHA256"`
+	Hosts          []string `json:"hosts"`          // 客户端使用的域名
+	UncoveredHosts []string `json:"uncoveredHosts"` // 证书不包含的域名
+	Error          string   `json:"error"`
+}
@@ -4,4 +4,11 @@ type JwtVo struct {
 	TokenType       string `json:"tokenType"`
 	AccessToken     string `json:"accessToken"`
 	TelegramWarning bool   `json:"telegram_warning,omitempty"`
+	PendingId       string `json:"pendingId,omitempty"` // waiting for Telegram approval
+}
+
+type LoginStatusVo struct {
+	Status      string `json:"status"`
+	TokenType   string `json:"tokenType,omitempty"`
+	AccessToken string `json:"accessToken,omitempty"`
 }
@@ -1,5 +1,7 @@
 package vo
 
+import "h-ui/model/bo"
+
 type SystemMonitorVo struct {
 	HUIVersion  string  `json:"huiVersion"`
 	CpuPercent  float64 `json:"cpuPercent"`
@@ -13,3 +15,13 @@ type Hysteria2MonitorVo struct {
 	Version     string `json:"version"`     // 版本
 	Running     bool   `json:"running"`     // 运行状态
 }
+
+type SystemMetricVo struct {
+	Time        int64          `json:"time"`
+	CpuPercent  float64        `json:"cpuPercent"`
+	MemPercent  float64        `json:"memPercent"`
+	Disks       []bo.DiskUsage `json:"disks"`
+	Nets        []bo.NetRate   `json:"nets"`
+	UserTotal   int64          `json:"userTotal"`
+	DeviceTotal int64          `json:"deviceTotal"`
+}
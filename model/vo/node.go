@@ -0,0 +1,13 @@
+package vo
+
+type NodeVo struct {
+	BaseVo
+	Name         string `json:"name"`
+	Host         string `json:"host"`
+	Port         int64  `json:"port"`
+	PortHopping  string `json:"portHopping"`
+	Sni          string `json:"sni"`
+	ObfsPassword string `json:"obfsPassword"`
+	Sort         int64  `json:"sort"`
+	Enable       int64  `json:"enable"`
+}
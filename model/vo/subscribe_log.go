@@ -0,0 +1,42 @@
+package vo
+
+type SubscribeLogVo struct {
+	Id        int64  `json:"id"`
+	AccountId int64  `json:"accountId"`
+	Source    string `json:"source"`
+	Ip        string `json:"ip"`
+	UserAgent string `json:"userAgent"`
+	Format    string `json:"format"`
+	AccessAt  int64  `json:"accessAt"`
+}
+
+type SubscribeLogPageVo struct {
+	SubscribeLogVos []SubscribeLogVo `json:"records"`
+	Total           int64            `json:"total"`
+}
+
+type SubscribeLogStatItemVo struct {
+	Value   string `json:"value"`
+	Count   int64  `json:"count"`
+	FirstAt int64  `json:"firstAt"`
+	LastAt  int64  `json:"lastAt"`
+}
+
+type SubscribeLogStatVo struct {
+	Ips        []SubscribeLogStatItemVo `json:"ips"`
+	UserAgents []SubscribeLogStatItemVo `json:"userAgents"`
+}
+
+type ShareFlagVo struct {
+	Id        int64    `json:"id"`
+	AccountId int64    `json:"accountId"`
+	IpCount   int64    `json:"ipCount"`
+	Ips       []string `json:"ips"`
+	Action    string   `json:"action"`
+	FlagAt    int64    `json:"flagAt"`
+}
+
+type ShareFlagPageVo struct {
+	ShareFlagVos []ShareFlagVo `json:"records"`
+	Total        int64         `json:"total"`
+}
@@ -0,0 +1,29 @@
+package vo
+
+type WebhookVo struct {
+	BaseVo
+	Name      string `json:"name"`
+	Url       string `json:"url"`
+	HasSecret bool   `json:"hasSecret"` // 不返回密钥，只表示是否已设置
+	Events    string `json:"events"`
+	Enable    int64  `json:"enable"`
+}
+
+type WebhookDeliveryVo struct {
+	Id           int64  `json:"id"`
+	WebhookId    int64  `json:"webhookId"`
+	Event        string `json:"event"`
+	Payload      string `json:"payload"`
+	Status       string `json:"status"`
+	Attempts     int64  `json:"attempts"`
+	ResponseCode int64  `json:"responseCode"`
+	Error        string `json:"error"`
+	NextRetryAt  int64  `json:"nextRetryAt"`
+	DeliveredAt  int64  `json:"deliveredAt"`
+	EventAt      int64  `json:"eventAt"`
+}
+
+type WebhookDeliveryPageVo struct {
+	WebhookDeliveryVos []WebhookDeliveryVo `json:"records"`
+	Total              int64               `json:"total"`
+}
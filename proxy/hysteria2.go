@@ -27,6 +27,11 @@ func NewHysteria2Instance() *Hysteria2Process {
 	return hysteria2Instance
 }
 
+// OnExit 设置 Hysteria2 异常退出时的回调
+func (h *Hysteria2Process) OnExit(onExit func(err error)) {
+	h.onExit = onExit
+}
+
 func (h *Hysteria2Process) IsRunning() bool {
 	return h.isRunning()
 }
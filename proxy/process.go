@@ -31,8 +31,9 @@ func init() {
 }
 
 type process struct {
-	mutex *sync.Mutex
-	cmd   *exec.Cmd
+	mutex  *sync.Mutex
+	cmd    *exec.Cmd
+	onExit func(err error) // 进程非主动停止时回调
 }
 
 func (p *process) isRunning() bool {
@@ -75,11 +76,31 @@ func (p *process) start(name string, arg ...string) error {
 
 	p.cmd = cmd
 
-	go p.handleLogs(stdout, stderr)
+	go func() {
+		p.handleLogs(stdout, stderr)
+		p.wait(cmd)
+	}()
 
 	return nil
 }
 
+// wait 回收进程，stop 和 release 会先置空 p.cmd，此时不算异常退出
+func (p *process) wait(cmd *exec.Cmd) {
+	err := cmd.Wait()
+	p.mutex.Lock()
+	if p.cmd != cmd {
+		p.mutex.Unlock()
+		return
+	}
+	p.cmd = nil
+	p.mutex.Unlock()
+	logrus.Errorf("cmd exited unexpectedly: %v", err)
+	// onExit 可能重新启动进程，需要在释放锁之后调用
+	if p.onExit != nil {
+		p.onExit(err)
+	}
+}
+
 func (p *process) stop() error {
 	if !p.mutex.TryLock() {
 		logrus.Errorf("cmd stop err: lock not acquired")
@@ -91,11 +112,13 @@ func (p *process) stop() error {
 		return nil
 	}
 
-	if err := p.cmd.Process.Kill(); err != nil {
+	cmd := p.cmd
+	p.cmd = nil
+	if err := cmd.Process.Kill(); err != nil {
+		p.cmd = cmd
 		logrus.Errorf("cmd stop err: %v", err)
 		return errors.New("cmd stop err")
 	}
-	p.cmd = nil
 	return nil
 }
 
@@ -110,11 +133,13 @@ func (p *process) release() error {
 		return nil
 	}
 
-	if err := p.cmd.Process.Release(); err != nil {
+	cmd := p.cmd
+	p.cmd = nil
+	if err := cmd.Process.Release(); err != nil {
+		p.cmd = cmd
 		logrus.Errorf("cmd release err: %v", err)
 		return errors.New("cmd release err")
 	}
-	p.cmd = nil
 	return nil
 }
 
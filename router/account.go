@@ -19,5 +19,11 @@ func initAccountAdminRouter(accountApi *gin.RouterGroup) {
 		account.POST("/exportAccount", controller.ExportAccount)
 		account.POST("/releaseKickAccount", controller.ReleaseKickAccount)
 		account.GET("/verifyDefaultPass", controller.VerifyDefaultPass)
+		account.GET("/pageLoginHistory", controller.PageLoginHistory)
+		account.POST("/telegramBindCode", controller.TelegramBindCode)
+		account.POST("/telegramUnbind", controller.TelegramUnbind)
+		account.GET("/pageSubscribeLog", controller.PageSubscribeLog)
+		account.GET("/subscribeLogStat", controller.SubscribeLogStat)
+		account.GET("/pageShareFlag", controller.PageShareFlag)
 	}
 }
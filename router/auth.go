@@ -9,5 +9,6 @@ func initAuthRouter(authApi *gin.RouterGroup) {
 	auth := authApi.Group("/auth")
 	{
 		auth.POST("/login", controller.Login)
+		auth.GET("/loginStatus", controller.LoginStatus)
 	}
 }
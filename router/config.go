@@ -20,5 +20,8 @@ func initConfigRouter(configApi *gin.RouterGroup) {
 		config.GET("/hysteria2AcmePath", controller.Hysteria2AcmePath)
 		config.POST("/restartServer", controller.RestartServer)
 		config.POST("/uploadCertFile", controller.UploadCertFile)
+		config.POST("/generateManagedCert", controller.GenerateManagedCert)
+		config.POST("/rotateManagedCert", controller.RotateManagedCert)
+		config.GET("/listCertInfo", controller.ListCertInfo)
 	}
 }
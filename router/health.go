@@ -0,0 +1,11 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"h-ui/controller"
+)
+
+func initHealthRouter(healthApi *gin.RouterGroup) {
+	healthApi.GET("/healthz", controller.Healthz)
+	healthApi.GET("/readyz", controller.Readyz)
+}
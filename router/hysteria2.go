@@ -11,7 +11,10 @@ func initHysteria2AuthRouter(hysteria2Api *gin.RouterGroup) {
 		hysteria2.POST("/auth", controller.Hysteria2Auth)
 
 	}
-	hysteria2Api.GET("/:conPass", controller.Hysteria2Subscribe)
+}
+
+func initSubscribeRouter(subscribeApi *gin.RouterGroup) {
+	subscribeApi.GET("/:conPass", controller.Hysteria2Subscribe)
 }
 
 func initHysteria2Router(hysteria2Api *gin.RouterGroup) {
@@ -22,5 +25,7 @@ func initHysteria2Router(hysteria2Api *gin.RouterGroup) {
 		hysteria2.GET("/listRelease", controller.ListRelease)
 		hysteria2.GET("/hysteria2SubscribeUrl", controller.Hysteria2SubscribeUrl)
 		hysteria2.GET("/hysteria2Url", controller.Hysteria2Url)
+		hysteria2.POST("/clashPreview", controller.ClashPreview)
+		hysteria2.POST("/hysteria2ClientConfig", controller.Hysteria2ClientConfig)
 	}
 }
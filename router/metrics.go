@@ -0,0 +1,10 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"h-ui/controller"
+)
+
+func initMetricsRouter(metricsApi *gin.RouterGroup) {
+	metricsApi.GET("/metrics", controller.Metrics)
+}
@@ -10,5 +10,6 @@ func initMonitorRouter(accountApi *gin.RouterGroup) {
 	{
 		account.GET("/monitorSystem", controller.MonitorSystem)
 		account.GET("/monitorHysteria2", controller.MonitorHysteria2)
+		account.GET("/listSystemMetric", controller.ListSystemMetric)
 	}
 }
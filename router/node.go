@@ -0,0 +1,16 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"h-ui/controller"
+)
+
+func initNodeRouter(nodeApi *gin.RouterGroup) {
+	node := nodeApi.Group("/node")
+	{
+		node.GET("/listNode", controller.ListNode)
+		node.POST("/saveNode", controller.SaveNode)
+		node.POST("/updateNode", controller.UpdateNode)
+		node.POST("/deleteNode", controller.DeleteNode)
+	}
+}
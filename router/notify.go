@@ -0,0 +1,13 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"h-ui/controller"
+)
+
+func initNotifyRouter(notifyApi *gin.RouterGroup) {
+	notify := notifyApi.Group("/notify")
+	{
+		notify.POST("/notifyTest", controller.NotifyTest)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"h-ui/frontend"
 	"h-ui/middleware"
+	"h-ui/model/constant"
 	"strings"
 )
 
@@ -13,18 +14,36 @@ func Router(router *gin.Engine, huiWebContext *string) {
 	if huiWebContext != nil && strings.HasPrefix(*huiWebContext, "/") {
 		relativePath = *huiWebContext
 	}
+	// 健康检查不经过扫描器过滤和限流，允许 curl 和 wget 探测
+	healthGroup := router.Group(relativePath)
+	initHealthRouter(healthGroup)
+
+	// 订阅接口允许 curl 和 wget，方便脚本拉取
+	subscribeGroup := router.Group(relativePath)
+	{
+		subscribeGroup.Use(middleware.MetricsHandler(), middleware.SubscribeFilterHandler(), middleware.IPFilterHandler(constant.IPFilterPublic), middleware.LogHandler(), middleware.RateLimiterHandler())
+		initSubscribeRouter(subscribeGroup.Group("/hui"))
+	}
+
 	globalGroup := router.Group(relativePath)
 	{
-		globalGroup.Use(middleware.FilterHandler(), middleware.LogHandler(), middleware.RateLimiterHandler())
+		globalGroup.Use(middleware.MetricsHandler(), middleware.FilterHandler(), middleware.LogHandler(), middleware.RateLimiterHandler())
 
 		frontend.InitFrontend(router, relativePath)
 
+		initMetricsRouter(globalGroup)
+
 		authApi := globalGroup.Group("/hui")
 		{
-			initAuthRouter(authApi)
+			// hysteria2 从本机调用认证接口，只过滤登录接口
 			initHysteria2AuthRouter(authApi)
+			authApi.Use(middleware.IPFilterHandler(constant.IPFilterPublic))
+			initAuthRouter(authApi)
 		}
 
+		// 管理接口使用单独的 IP 名单，与订阅和登录接口分开
+		globalGroup.Use(middleware.IPFilterHandler(constant.IPFilterAdmin))
+
 		globalGroup.Use(middleware.JWTHandler())
 
 		globalGroup.Use(middleware.AdminHandler())
@@ -36,6 +55,10 @@ func Router(router *gin.Engine, huiWebContext *string) {
 			initHysteria2Router(huiAdminApi)
 			initLogRouter(huiAdminApi)
 			initMonitorRouter(huiAdminApi)
+			initAlertRouter(huiAdminApi)
+			initNotifyRouter(huiAdminApi)
+			initWebhookRouter(huiAdminApi)
+			initNodeRouter(huiAdminApi)
 		}
 	}
 }
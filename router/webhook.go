@@ -0,0 +1,18 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"h-ui/controller"
+)
+
+func initWebhookRouter(webhookApi *gin.RouterGroup) {
+	webhook := webhookApi.Group("/webhook")
+	{
+		webhook.GET("/listWebhook", controller.ListWebhook)
+		webhook.POST("/saveWebhook", controller.SaveWebhook)
+		webhook.POST("/updateWebhook", controller.UpdateWebhook)
+		webhook.POST("/deleteWebhook", controller.DeleteWebhook)
+		webhook.GET("/pageWebhookDelivery", controller.PageWebhookDelivery)
+		webhook.POST("/replayWebhookDelivery", controller.ReplayWebhookDelivery)
+	}
+}
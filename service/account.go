@@ -4,12 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 	"h-ui/dao"
 	"h-ui/model/bo"
 	"h-ui/model/constant"
 	"h-ui/model/dto"
 	"h-ui/model/entity"
 	"h-ui/model/vo"
+	"time"
 )
 
 func login(username string, pass string) (string, error) {
@@ -33,12 +35,31 @@ func PageAccount(accountPageDto dto.AccountPageDto) ([]entity.Account, int64, er
 }
 
 func SaveAccount(account entity.Account) error {
-	_, err := dao.SaveAccount(account)
-	return err
+	id, err := dao.SaveAccount(account)
+	if err != nil {
+		return err
+	}
+	go EmitAccountEventById(constant.AccountEventCreated, id, nil)
+	return nil
 }
 
 func DeleteAccount(ids []int64) error {
-	return dao.DeleteAccount(ids)
+	accounts, err := dao.ListAccount("id in ?", ids)
+	if err != nil {
+		return err
+	}
+	if err := dao.DeleteAccount(ids); err != nil {
+		return err
+	}
+	go func() {
+		for _, item := range accounts {
+			EmitAccountEvent(constant.AccountEventDeleted, item, nil)
+		}
+	}()
+	if err := dao.DeleteSubscribeLog("account_id in ?", ids); err != nil {
+		return err
+	}
+	return dao.DeleteTelegramBind("account_id in ?", ids)
 }
 
 func UpdateAccount(account entity.Account) error {
@@ -76,11 +97,32 @@ func UpdateAccount(account entity.Account) error {
 	if account.ConAt != nil && *account.ConAt > 0 {
 		updates["con_at"] = *account.ConAt
 	}
-	return dao.UpdateAccount([]int64{*account.Id}, updates)
+	if err := dao.UpdateAccount([]int64{*account.Id}, updates); err != nil {
+		return err
+	}
+	if account.Deleted != nil && *account.Deleted == 1 && Hysteria2IsRunning() {
+		// 禁用后立即断开在线连接，不改变已有的禁止时间
+		if item, err := dao.GetAccount("id = ?", *account.Id); err == nil {
+			if err = hysteria2Kick([]int64{*item.Id}, max(*item.KickUtilTime, time.Now().UnixMilli()), "disabled"); err != nil {
+				logrus.Warnf("kick disabled account %s err: %v", *item.Username, err)
+			}
+		}
+	}
+	// 只更新登录和连接时间时不算账户变更
+	delete(updates, "login_at")
+	delete(updates, "con_at")
+	if len(updates) > 0 {
+		go EmitAccountEventById(constant.AccountEventUpdated, *account.Id, nil)
+	}
+	return nil
 }
 
 func ResetTraffic(id int64) error {
-	return dao.UpdateAccount([]int64{id}, map[string]interface{}{"download": 0, "upload": 0})
+	if err := dao.UpdateAccount([]int64{id}, map[string]interface{}{"download": 0, "upload": 0}); err != nil {
+		return err
+	}
+	go EmitAccountEventById(constant.AccountEventTrafficReset, id, nil)
+	return nil
 }
 
 func existAccountUsername(username string, id int64) bool {
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"h-ui/dao"
+	"h-ui/model/constant"
+	"h-ui/model/entity"
+	"sync"
+	"time"
+)
+
+var accountEventMutex sync.Mutex
+
+// accountExpiredSent 已发送过到期事件的账户和对应的到期时间，续期后会重新发送
+var accountExpiredSent = map[int64]int64{}
+var accountExpiredMutex sync.Mutex
+var accountEventCheckAt int64
+
+// accountExpired 账户到期时通知管理员并触发 webhook，同一到期时间只发送一次
+func accountExpired(account entity.Account) {
+	accountExpiredMutex.Lock()
+	if accountExpiredSent[*account.Id] == *account.ExpireTime {
+		accountExpiredMutex.Unlock()
+		return
+	}
+	accountExpiredSent[*account.Id] = *account.ExpireTime
+	accountExpiredMutex.Unlock()
+
+	text := fmt.Sprintf("%s expired at %s",
+		*account.Username, time.UnixMilli(*account.ExpireTime).Format("2006-01-02 15:04:05"))
+	if err := Notify(constant.NotifyEventExpiry, text); err != nil && err != ErrNotifyNoChannel {
+		logrus.Warnf("notify expiry err: %v", err)
+	}
+	EmitAccountEvent(constant.AccountEventExpired, account, nil)
+}
+
+// CronAccountEvent 检查不在线账户的到期，在线账户由 kickAccount 处理
+func CronAccountEvent() {
+	if !accountEventMutex.TryLock() {
+		return
+	}
+	defer accountEventMutex.Unlock()
+
+	now := time.Now().UnixMilli()
+	// 首次运行只记录时间，避免面板重启后重复通知
+	if accountEventCheckAt == 0 {
+		accountEventCheckAt = now
+		return
+	}
+	accounts, err := dao.ListAccount("deleted = 0 and expire_time > ? and expire_time <= ?", accountEventCheckAt, now)
+	if err != nil {
+		return
+	}
+	for _, item := range accounts {
+		accountExpired(item)
+	}
+	accountEventCheckAt = now
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"h-ui/dao"
+	"h-ui/model/bo"
+	"h-ui/model/constant"
+	"h-ui/model/dto"
+	"h-ui/model/entity"
+	"sync"
+	"time"
+)
+
+type alertState struct {
+	pendingSince  time.Time // 条件首次满足的时间
+	firing        bool
+	lastNotify    time.Time // 上次发送 FIRING 的时间
+	pendingNotify bool      // FIRING 因冷却或发送失败未送达，冷却结束后补发
+}
+
+// alertEvent 需要在释放 alertMutex 之后发送的通知
+type alertEvent struct {
+	alertRule entity.AlertRule
+	state     string
+	value     float64
+	notify    bool
+	record    bool // 状态变化时记录历史，补发的 FIRING 不重复记录
+}
+
+// alertCronMutex 避免定时任务重叠执行，alertMutex 只保护 alertStates
+var alertCronMutex sync.Mutex
+var alertMutex sync.Mutex
+var alertStates = map[int64]*alertState{}
+
+func SaveAlertRule(alertRule entity.AlertRule) error {
+	_, err := dao.SaveAlertRule(alertRule)
+	return err
+}
+
+func UpdateAlertRule(alertRule entity.AlertRule) error {
+	updates := map[string]interface{}{}
+	if alertRule.Name != nil && *alertRule.Name != "" {
+		updates["name"] = *alertRule.Name
+	}
+	if alertRule.Type != nil && *alertRule.Type != "" {
+		updates["type"] = *alertRule.Type
+	}
+	if alertRule.Threshold != nil {
+		updates["threshold"] = *alertRule.Threshold
+	}
+	if alertRule.Duration != nil {
+		updates["duration"] = *alertRule.Duration
+	}
+	if alertRule.Cooldown != nil {
+		updates["cooldown"] = *alertRule.Cooldown
+	}
+	if alertRule.Enable != nil {
+		updates["enable"] = *alertRule.Enable
+	}
+	if err := dao.UpdateAlertRule([]int64{*alertRule.Id}, updates); err != nil {
+		return err
+	}
+	alertMutex.Lock()
+	delete(alertStates, *alertRule.Id)
+	alertMutex.Unlock()
+	return nil
+}
+
+func DeleteAlertRule(ids []int64) error {
+	if err := dao.DeleteAlertRule(ids); err != nil {
+		return err
+	}
+	alertMutex.Lock()
+	for _, id := range ids {
+		delete(alertStates, id)
+	}
+	alertMutex.Unlock()
+	return nil
+}
+
+func ListAlertRule() ([]entity.AlertRule, error) {
+	return dao.ListAlertRule(nil, nil)
+}
+
+// AlertRuleState 规则当前状态
+func AlertRuleState(id int64) string {
+	alertMutex.Lock()
+	defer alertMutex.Unlock()
+	if state, ok := alertStates[id]; ok && state.firing {
+		return constant.AlertStateFiring
+	}
+	return constant.AlertStateResolved
+}
+
+func PageAlertHistory(alertHistoryPageDto dto.AlertHistoryPageDto) ([]entity.AlertHistory, int64, error) {
+	return dao.PageAlertHistory(alertHistoryPageDto)
+}
+
+func CronAlert() {
+	if !alertCronMutex.TryLock() {
+		return
+	}
+	defer alertCronMutex.Unlock()
+
+	alertRules, err := dao.ListAlertRule("enable = 1")
+	if err != nil || len(alertRules) == 0 {
+		return
+	}
+
+	// 指标可能需要请求 Hysteria2，在加锁之前获取
+	values := map[string]float64{}
+	for _, alertRule := range alertRules {
+		if _, ok := values[*alertRule.Type]; ok {
+			continue
+		}
+		if value, err := alertValue(*alertRule.Type); err == nil {
+			values[*alertRule.Type] = value
+		}
+	}
+
+	now := time.Now()
+	for _, event := range alertEvaluate(alertRules, values, now) {
+		alertSend(event, now)
+	}
+}
+
+// alertEvaluate 更新规则状态，返回需要发送的通知。
+// 冷却时间只限制重复的 FIRING，RESOLVED 总是发送
+func alertEvaluate(alertRules []entity.AlertRule, values map[string]float64, now time.Time) []alertEvent {
+	alertMutex.Lock()
+	defer alertMutex.Unlock()
+
+	var events []alertEvent
+	for _, alertRule := range alertRules {
+		value, ok := values[*alertRule.Type]
+		if !ok {
+			continue
+		}
+
+		state, ok := alertStates[*alertRule.Id]
+		if !ok {
+			state = &alertState{}
+			alertStates[*alertRule.Id] = state
+		}
+
+		cooldownPassed := now.Sub(state.lastNotify) >= time.Duration(*alertRule.Cooldown)*time.Minute
+		if alertMatch(alertRule, value) {
+			if state.pendingSince.IsZero() {
+				state.pendingSince = now
+			}
+			if !state.firing && now.Sub(state.pendingSince) >= time.Duration(*alertRule.Duration)*time.Minute {
+				state.firing = true
+				state.pendingNotify = !cooldownPassed
+				if cooldownPassed {
+					state.lastNotify = now
+				}
+				events = append(events, alertEvent{alertRule: alertRule, state: constant.AlertStateFiring,
+					value: value, notify: cooldownPassed, record: true})
+			} else if state.firing && state.pendingNotify && cooldownPassed {
+				state.pendingNotify = false
+				state.lastNotify = now
+				events = append(events, alertEvent{alertRule: alertRule, state: constant.AlertStateFiring,
+					value: value, notify: true})
+			}
+		} else {
+			state.pendingSince = time.Time{}
+			if state.firing {
+				state.firing = false
+				state.pendingNotify = false
+				events = append(events, alertEvent{alertRule: alertRule, state: constant.AlertStateResolved,
+					value: value, notify: true, record: true})
+			}
+		}
+	}
+	return events
+}
+
+// alertValue 当前指标值，状态类指标 1 表示异常
+func alertValue(alertType string) (float64, error) {
+	switch alertType {
+	case constant.AlertTypeCpu, constant.AlertTypeMem, constant.AlertTypeDisk:
+		systemMetric, err := dao.GetLatestSystemMetric()
+		if err != nil {
+			return 0, err
+		}
+		if time.Now().UnixMilli()-*systemMetric.SampleTime > 3*time.Minute.Milliseconds() {
+			return 0, errors.New("system metric is outdated")
+		}
+		if alertType == constant.AlertTypeCpu {
+			return *systemMetric.CpuPercent, nil
+		}
+		if alertType == constant.AlertTypeMem {
+			return *systemMetric.MemPercent, nil
+		}
+		var diskUsages []bo.DiskUsage
+		if err = json.Unmarshal([]byte(*systemMetric.Disks), &diskUsages); err != nil {
+			return 0, err
+		}
+		var maxPercent float64
+		for _, item := range diskUsages {
+			if item.Percent > maxPercent {
+				maxPercent = item.Percent
+			}
+		}
+		return maxPercent, nil
+	case constant.AlertTypeHysteria2Down:
+		hysteria2Enable, err := dao.GetConfig("key = ?", constant.Hysteria2Enable)
+		if err != nil {
+			return 0, err
+		}
+		if *hysteria2Enable.Value == "1" && !Hysteria2IsRunning() {
+			return 1, nil
+		}
+		return 0, nil
+	case constant.AlertTypeOnlineDevices:
+		onlineUsers, err := Hysteria2Online()
+		if err != nil {
+			return 0, err
+		}
+		var deviceTotal int64
+		for _, value := range onlineUsers {
+			deviceTotal += value
+		}
+		return float64(deviceTotal), nil
+	case constant.AlertTypeTrafficStats:
+		if !Hysteria2IsRunning() {
+			return 0, nil
+		}
+		if err := pingTrafficStats(); err != nil {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return 0, errors.New("unsupported alert type")
+}
+
+func alertMatch(alertRule entity.AlertRule, value float64) bool {
+	switch *alertRule.Type {
+	case constant.AlertTypeHysteria2Down, constant.AlertTypeTrafficStats:
+		return value > 0
+	default:
+		return value > *alertRule.Threshold
+	}
+}
+
+func alertMessage(alertRule entity.AlertRule, state string, value float64) string {
+	var detail string
+	switch *alertRule.Type {
+	case constant.AlertTypeCpu:
+		detail = fmt.Sprintf("CPU usage %.1f%% > %.1f%%", value, *alertRule.Threshold)
+	case constant.AlertTypeMem:
+		detail = fmt.Sprintf("Memory usage %.1f%% > %.1f%%", value, *alertRule.Threshold)
+	case constant.AlertTypeDisk:
+		detail = fmt.Sprintf("Disk usage %.1f%% > %.1f%%", value, *alertRule.Threshold)
+	case constant.AlertTypeHysteria2Down:
+		detail = "Hysteria2 is not running"
+	case constant.AlertTypeOnlineDevices:
+		detail = fmt.Sprintf("Online devices %.0f > %.0f", value, *alertRule.Threshold)
+	case constant.AlertTypeTrafficStats:
+		detail = "Hysteria2 Traffic Stats API is unreachable"
+	}
+	if state == constant.AlertStateResolved {
+		return fmt.Sprintf("[RESOLVED] %s", *alertRule.Name)
+	}
+	return fmt.Sprintf("[FIRING] %s: %s", *alertRule.Name, detail)
+}
+
+// alertSend 发送通知并记录历史，FIRING 发送失败时下次补发
+func alertSend(event alertEvent, now time.Time) {
+	message := alertMessage(event.alertRule, event.state, event.value)
+	var notified int64
+	if event.notify {
+		if err := Notify(constant.NotifyEventAlert, message); err != nil {
+			logrus.Warnf("alert notify err: %v", err)
+			if event.state == constant.AlertStateFiring {
+				alertMutex.Lock()
+				if state, ok := alertStates[*event.alertRule.Id]; ok && state.firing {
+					state.pendingNotify = true
+					state.lastNotify = time.Time{}
+				}
+				alertMutex.Unlock()
+			}
+		} else {
+			notified = 1
+		}
+	}
+	if !event.record {
+		return
+	}
+	alertAt := now.UnixMilli()
+	if _, err := dao.SaveAlertHistory(entity.AlertHistory{
+		RuleId:   event.alertRule.Id,
+		RuleName: event.alertRule.Name,
+		Type:     event.alertRule.Type,
+		State:    &event.state,
+		Value:    &event.value,
+		Message:  &message,
+		Notified: &notified,
+		AlertAt:  &alertAt,
+	}); err != nil {
+		logrus.Errorf("save alert history err: %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"h-ui/model/constant"
+	"h-ui/model/entity"
+)
+
+func TestAlertEvaluateCooldown(t *testing.T) {
+	id := int64(-1)
+	name := "cpu"
+	alertType := constant.AlertTypeCpu
+	threshold := float64(80)
+	duration := int64(0)
+	cooldown := int64(10)
+	alertRule := entity.AlertRule{Name: &name, Type: &alertType,
+		Threshold: &threshold, Duration: &duration, Cooldown: &cooldown}
+	alertRule.Id = &id
+	alertRules := []entity.AlertRule{alertRule}
+	defer delete(alertStates, id)
+
+	start := time.Now()
+	steps := []struct {
+		minute int
+		value  float64
+		state  string // 空表示不发送通知
+		record bool
+	}{
+		{0, 90, constant.AlertStateFiring, true},
+		{1, 10, constant.AlertStateResolved, true},
+		// 冷却中的 FIRING 只记录历史，不发送
+		{2, 90, "", true},
+		{3, 90, "", false},
+		// 冷却结束后补发
+		{10, 90, constant.AlertStateFiring, false},
+		{11, 90, "", false},
+		{12, 10, constant.AlertStateResolved, true},
+	}
+	for _, step := range steps {
+		events := alertEvaluate(alertRules, map[string]float64{alertType: step.value},
+			start.Add(time.Duration(step.minute)*time.Minute))
+		var state string
+		var record bool
+		for _, event := range events {
+			if event.notify {
+				state = event.state
+			}
+			record = record || event.record
+		}
+		if state != step.state || record != step.record {
+			t.Fatalf("minute %d: notify %q record %v, want %q %v", step.minute, state, record, step.state, step.record)
+		}
+	}
+}
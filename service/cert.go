@@ -0,0 +1,390 @@
+package service
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"h-ui/dao"
+	"h-ui/model/constant"
+	"h-ui/model/vo"
+	"h-ui/util"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// certPath 面板生成的证书和私钥的绝对路径
+func certPath(name string) (string, string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", "", err
+	}
+	return filepath.Join(wd, constant.CertDir, name+".crt"), filepath.Join(wd, constant.CertDir, name+".key"), nil
+}
+
+// isManagedCert 证书是否由面板生成
+func isManagedCert(path string) bool {
+	wd, err := os.Getwd()
+	if err != nil {
+		return false
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	return filepath.Dir(absPath) == filepath.Join(wd, filepath.Clean(constant.CertDir))
+}
+
+// readCert 读取 PEM 证书文件中的第一个证书
+func readCert(path string) (*x509.Certificate, error) {
+	certPEM, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("%s is not a pem certificate", path)
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// readKey 读取 PEM 私钥，支持 SEC 1 格式的 EC 私钥和 PKCS#8 格式的私钥
+func readKey(path string) (crypto.Signer, error) {
+	keyPEM, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a pem private key", path)
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not an ec or pkcs8 private key: %v", path, err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%s private key type %T is not supported", path, key)
+	}
+	return signer, nil
+}
+
+// certPinSHA256 证书 DER 的 SHA-256，和 hysteria2 客户端的 pinSHA256 格式一致
+func certPinSHA256(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// managedCertPin 证书由面板生成时返回指纹，否则返回空
+func managedCertPin(path string) string {
+	if path == "" || !isManagedCert(path) {
+		return ""
+	}
+	cert, err := readCert(path)
+	if err != nil {
+		return ""
+	}
+	return certPinSHA256(cert)
+}
+
+// createCert 签发证书，parent 为空时自签
+func createCert(template *x509.Certificate, parent *x509.Certificate, parentKey crypto.Signer) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+	template.SerialNumber = serialNumber
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+func writeCert(name string, cert *x509.Certificate, key *ecdsa.PrivateKey) (string, string, error) {
+	crtPath, keyPath, err := certPath(name)
+	if err != nil {
+		return "", "", err
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return "", "", err
+	}
+	if err = os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		return "", "", err
+	}
+	if err = os.WriteFile(crtPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0644); err != nil {
+		return "", "", err
+	}
+	return crtPath, keyPath, nil
+}
+
+// managedCa 面板的 CA，不存在时生成，有效期 10 年
+func managedCa() (*x509.Certificate, crypto.Signer, error) {
+	crtPath, keyPath, err := certPath(constant.CertCaName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if util.Exists(crtPath) && util.Exists(keyPath) {
+		ca, err := readCert(crtPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		caKey, err := readKey(keyPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		if time.Now().Before(ca.NotAfter) {
+			return ca, caKey, nil
+		}
+	}
+	now := time.Now()
+	ca, caKey, err := createCert(&x509.Certificate{
+		Subject:               pkix.Name{CommonName: "H UI CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, _, err = writeCert(constant.CertCaName, ca, caKey); err != nil {
+		return nil, nil, err
+	}
+	return ca, caKey, nil
+}
+
+// generateManagedCert 生成 name 对应的证书，useCa 为 true 时由面板 CA 签发，否则自签
+func generateManagedCert(name string, hosts []string, days int64, useCa bool) (*x509.Certificate, string, string, error) {
+	if len(hosts) == 0 {
+		return nil, "", "", errors.New("cert hosts is empty")
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: hosts[0]},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Duration(days) * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	var parent *x509.Certificate
+	var parentKey crypto.Signer
+	if useCa {
+		var err error
+		if parent, parentKey, err = managedCa(); err != nil {
+			return nil, "", "", err
+		}
+	}
+	cert, key, err := createCert(template, parent, parentKey)
+	if err != nil {
+		return nil, "", "", err
+	}
+	crtPath, keyPath, err := writeCert(name, cert, key)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return cert, crtPath, keyPath, nil
+}
+
+// errHysteria2ConfigAcme hysteria2 配置了 acme 时不覆盖，需要管理员先删除 acme
+var errHysteria2ConfigAcme = errors.New("hysteria2 config uses acme, remove the acme block before generating a certificate")
+
+// hysteria2ConfigHasAcme HYSTERIA2_CONFIG 中是否配置了 acme
+func hysteria2ConfigHasAcme() (bool, error) {
+	hysteria2Config, err := GetHysteria2Config()
+	if err != nil {
+		return false, err
+	}
+	return hysteria2Config.ACME != nil, nil
+}
+
+// setHysteria2ConfigTLS 修改 HYSTERIA2_CONFIG 中的 tls 证书，配置了 acme 时返回错误，其它配置保持不变
+func setHysteria2ConfigTLS(crtPath string, keyPath string) error {
+	config, err := dao.GetConfig("key = ?", constant.Hysteria2Config)
+	if err != nil {
+		return err
+	}
+	var root yaml.Node
+	if err = yaml.Unmarshal([]byte(*config.Value), &root); err != nil {
+		return err
+	}
+	if len(root.Content) == 0 {
+		root = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	mapping := root.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return errors.New("hysteria2 config is invalid")
+	}
+	var tls *yaml.Node
+	var content []*yaml.Node
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		switch mapping.Content[i].Value {
+		case "acme":
+			if mapping.Content[i+1].Tag != "!!null" {
+				return errHysteria2ConfigAcme
+			}
+		case "tls":
+			if mapping.Content[i+1].Kind != yaml.MappingNode {
+				continue
+			}
+			tls = mapping.Content[i+1]
+		}
+		content = append(content, mapping.Content[i], mapping.Content[i+1])
+	}
+	if tls == nil {
+		tls = &yaml.Node{Kind: yaml.MappingNode}
+		content = append(content, &yaml.Node{Kind: yaml.ScalarNode, Value: "tls"}, tls)
+	}
+	mapping.Content = content
+	setYamlValue(tls, "cert", crtPath)
+	setYamlValue(tls, "key", keyPath)
+
+	value, err := yaml.Marshal(&root)
+	if err != nil {
+		return err
+	}
+	return dao.UpdateConfig([]string{constant.Hysteria2Config}, map[string]interface{}{"value": string(value)})
+}
+
+func setYamlValue(mapping *yaml.Node, key string, value string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: key},
+		&yaml.Node{Kind: yaml.ScalarNode, Value: value})
+}
+
+// GenerateManagedCert 为 hysteria2 或面板生成证书并启用，hysteria2 正在运行时会重启，面板需要重启后生效
+func GenerateManagedCert(target string, hosts []string, days int64, useCa bool) (vo.ManagedCertVo, error) {
+	var cleanHosts []string
+	for _, host := range hosts {
+		if host = strings.Trim(strings.TrimSpace(host), "[]"); host != "" {
+			cleanHosts = append(cleanHosts, host)
+		}
+	}
+	// 先检查 acme，避免生成了证书却无法启用
+	if target == constant.CertHysteria2Name {
+		hasAcme, err := hysteria2ConfigHasAcme()
+		if err != nil {
+			return vo.ManagedCertVo{}, err
+		}
+		if hasAcme {
+			return vo.ManagedCertVo{}, errHysteria2ConfigAcme
+		}
+	}
+	cert, crtPath, keyPath, err := generateManagedCert(target, cleanHosts, days, useCa)
+	if err != nil {
+		return vo.ManagedCertVo{}, err
+	}
+
+	switch target {
+	case constant.CertHysteria2Name:
+		if err = setHysteria2ConfigTLS(crtPath, keyPath); err != nil {
+			return vo.ManagedCertVo{}, err
+		}
+		if Hysteria2IsRunning() {
+			if err = RestartHysteria2(); err != nil {
+				return vo.ManagedCertVo{}, err
+			}
+		}
+	case constant.CertPanelName:
+		if err = dao.UpdateConfig([]string{constant.HUICrtPath}, map[string]interface{}{"value": crtPath}); err != nil {
+			return vo.ManagedCertVo{}, err
+		}
+		if err = dao.UpdateConfig([]string{constant.HUIKeyPath}, map[string]interface{}{"value": keyPath}); err != nil {
+			return vo.ManagedCertVo{}, err
+		}
+	}
+
+	var caCert string
+	if useCa {
+		caCrtPath, _, err := certPath(constant.CertCaName)
+		if err != nil {
+			return vo.ManagedCertVo{}, err
+		}
+		caPEM, err := os.ReadFile(caCrtPath)
+		if err != nil {
+			return vo.ManagedCertVo{}, err
+		}
+		caCert = string(caPEM)
+	}
+
+	return vo.ManagedCertVo{
+		Target:    target,
+		CrtPath:   crtPath,
+		KeyPath:   keyPath,
+		Hosts:     cleanHosts,
+		CaCert:    caCert,
+		PinSHA256: certPinSHA256(cert),
+		NotAfter:  cert.NotAfter.UnixMilli(),
+	}, nil
+}
+
+// RotateManagedCert 使用原证书的域名、有效期和签发方式重新生成
+func RotateManagedCert(target string) (vo.ManagedCertVo, error) {
+	crtPath, _, err := certPath(target)
+	if err != nil {
+		return vo.ManagedCertVo{}, err
+	}
+	if !util.Exists(crtPath) {
+		return vo.ManagedCertVo{}, fmt.Errorf("%s cert is not generated by the panel", target)
+	}
+	cert, err := readCert(crtPath)
+	if err != nil {
+		return vo.ManagedCertVo{}, err
+	}
+	var hosts []string
+	hosts = append(hosts, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		hosts = append(hosts, ip.String())
+	}
+	days := int64(cert.NotAfter.Sub(cert.NotBefore).Hours()/24 + 0.5)
+	// 能用面板 CA 验证签名的才是 CA 签发，否则按自签处理
+	useCa := false
+	if caCrtPath, _, err := certPath(constant.CertCaName); err == nil && util.Exists(caCrtPath) {
+		if ca, err := readCert(caCrtPath); err == nil {
+			useCa = cert.CheckSignatureFrom(ca) == nil
+		}
+	}
+	return GenerateManagedCert(target, hosts, days, useCa)
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"crypto/tls"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"h-ui/dao"
+	"h-ui/model/constant"
+	"h-ui/model/vo"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var certCheckMutex sync.Mutex
+
+// certNotifiedAt 已通知的证书和通知日期，每个证书每天最多通知一次
+var certNotifiedAt = map[string]string{}
+
+// inspectCert 解析证书并检查私钥和域名，读取失败时只填写 Error
+func inspectCert(target string, crtPath string, keyPath string, hosts []string, now time.Time) vo.CertInfoVo {
+	certInfoVo := vo.CertInfoVo{
+		Target:         target,
+		CrtPath:        crtPath,
+		KeyPath:        keyPath,
+		DnsNames:       []string{},
+		IpAddresses:    []string{},
+		Hosts:          hosts,
+		UncoveredHosts: []string{},
+	}
+	cert, err := readCert(crtPath)
+	if err != nil {
+		certInfoVo.Error = err.Error()
+		return certInfoVo
+	}
+	certInfoVo.Subject = cert.Subject.String()
+	certInfoVo.Issuer = cert.Issuer.String()
+	certInfoVo.DnsNames = append(certInfoVo.DnsNames, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		certInfoVo.IpAddresses = append(certInfoVo.IpAddresses, ip.String())
+	}
+	certInfoVo.NotBefore = cert.NotBefore.UnixMilli()
+	certInfoVo.NotAfter = cert.NotAfter.UnixMilli()
+	certInfoVo.DaysLeft = int64(cert.NotAfter.Sub(now).Hours() / 24)
+	certInfoVo.SelfSigned = cert.Issuer.String() == cert.Subject.String() &&
+		cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+	certInfoVo.Managed = isManagedCert(crtPath)
+	certInfoVo.PinSHA256 = certPinSHA256(cert)
+	if _, err = tls.LoadX509KeyPair(crtPath, keyPath); err == nil {
+		certInfoVo.KeyMatch = true
+	} else {
+		certInfoVo.Error = err.Error()
+	}
+	for _, host := range hosts {
+		if cert.VerifyHostname(host) != nil {
+			certInfoVo.UncoveredHosts = append(certInfoVo.UncoveredHosts, host)
+		}
+	}
+	return certInfoVo
+}
+
+// panelCertHosts 面板的证书应包含 HUI_PUBLIC_URL 的域名
+func panelCertHosts() ([]string, error) {
+	publicUrl, err := dao.GetConfig("key = ?", constant.HUIPublicUrl)
+	if err != nil {
+		return nil, err
+	}
+	var hosts []string
+	if *publicUrl.Value != "" {
+		if parsed, err := url.Parse(*publicUrl.Value); err == nil && parsed.Hostname() != "" {
+			hosts = append(hosts, parsed.Hostname())
+		}
+	}
+	return hosts, nil
+}
+
+// hysteria2CertHosts hysteria2 的证书应包含 ACME 域名和 SUBSCRIBE_SERVER
+func hysteria2CertHosts() ([]string, error) {
+	hysteria2Config, err := GetHysteria2Config()
+	if err != nil {
+		return nil, err
+	}
+	var hosts []string
+	if hysteria2Config.ACME != nil {
+		hosts = append(hosts, hysteria2Config.ACME.Domains...)
+	}
+	subscribeServer, err := dao.GetConfig("key = ?", constant.SubscribeServer)
+	if err != nil {
+		return nil, err
+	}
+	if *subscribeServer.Value != "" {
+		hosts = append(hosts, strings.Trim(*subscribeServer.Value, "[]"))
+	}
+	return hosts, nil
+}
+
+// ListCertInfo 面板和 hysteria2 当前使用的证书，未配置证书的不返回
+func ListCertInfo() ([]vo.CertInfoVo, error) {
+	return listCertInfo(time.Now())
+}
+
+func listCertInfo(now time.Time) ([]vo.CertInfoVo, error) {
+	certInfoVos := []vo.CertInfoVo{}
+
+	_, crtPath, keyPath, err := GetPortAndCert()
+	if err != nil {
+		return nil, err
+	}
+	if crtPath != "" {
+		hosts, err := panelCertHosts()
+		if err != nil {
+			return nil, err
+		}
+		certInfoVos = append(certInfoVos, inspectCert(constant.CertPanelName, crtPath, keyPath, hosts, now))
+	}
+
+	// 包括 ACME 目录中签发的证书，配置的证书文件不存在时也返回以便显示错误
+	crtPath, keyPath = "", ""
+	if acmePath, err := Hysteria2AcmePath(); err == nil {
+		crtPath, keyPath = acmePath.CrtPath, acmePath.KeyPath
+	} else if hysteria2Config, err := GetHysteria2Config(); err == nil &&
+		hysteria2Config.TLS != nil && hysteria2Config.TLS.Cert != nil && hysteria2Config.TLS.Key != nil {
+		crtPath, keyPath = *hysteria2Config.TLS.Cert, *hysteria2Config.TLS.Key
+	}
+	if crtPath != "" {
+		hosts, err := hysteria2CertHosts()
+		if err != nil {
+			return nil, err
+		}
+		certInfoVos = append(certInfoVos, inspectCert(constant.CertHysteria2Name, crtPath, keyPath, hosts, now))
+	}
+	return certInfoVos, nil
+}
+
+// certProblem 需要通知的问题，多个问题用分号分隔，没有问题时返回空
+func certProblem(certInfoVo vo.CertInfoVo, warnDays int64, now time.Time) string {
+	if certInfoVo.NotAfter == 0 {
+		return fmt.Sprintf("cannot be read: %s", certInfoVo.Error)
+	}
+	var problems []string
+	if certInfoVo.DaysLeft < 0 || now.UnixMilli() >= certInfoVo.NotAfter {
+		problems = append(problems, fmt.Sprintf("expired at %s",
+			time.UnixMilli(certInfoVo.NotAfter).Format("2006-01-02 15:04:05")))
+	} else if certInfoVo.DaysLeft < warnDays {
+		problems = append(problems, fmt.Sprintf("expires in %d days at %s",
+			certInfoVo.DaysLeft, time.UnixMilli(certInfoVo.NotAfter).Format("2006-01-02 15:04:05")))
+	}
+	if !certInfoVo.KeyMatch {
+		problems = append(problems, fmt.Sprintf("does not match its key: %s", certInfoVo.Error))
+	}
+	if len(certInfoVo.UncoveredHosts) > 0 {
+		problems = append(problems, fmt.Sprintf("does not cover %s", strings.Join(certInfoVo.UncoveredHosts, ", ")))
+	}
+	return strings.Join(problems, "; ")
+}
+
+// CronCertCheck 证书即将到期、已到期或与私钥不匹配时通知管理员
+func CronCertCheck() {
+	if !certCheckMutex.TryLock() {
+		return
+	}
+	defer certCheckMutex.Unlock()
+
+	warnDaysConfig, err := dao.GetConfig("key = ?", constant.CertExpireWarnDays)
+	if err != nil {
+		return
+	}
+	warnDays, _ := strconv.ParseInt(*warnDaysConfig.Value, 10, 64)
+	if warnDays <= 0 {
+		return
+	}
+	now := time.Now()
+	certInfoVos, err := listCertInfo(now)
+	if err != nil {
+		return
+	}
+	today := now.Format("2006-01-02")
+	for _, item := range certInfoVos {
+		problem := certProblem(item, warnDays, now)
+		if problem == "" {
+			delete(certNotifiedAt, item.CrtPath)
+			continue
+		}
+		if certNotifiedAt[item.CrtPath] == today {
+			continue
+		}
+		// 发送失败时不记录，下次检查重新通知
+		text := fmt.Sprintf("%s certificate %s %s", item.Target, item.CrtPath, problem)
+		if err = Notify(constant.NotifyEventCert, text); err != nil && err != ErrNotifyNoChannel {
+			logrus.Warnf("notify cert err: %v", err)
+			continue
+		}
+		certNotifiedAt[item.CrtPath] = today
+	}
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"h-ui/dao"
+	"h-ui/model/constant"
+	"h-ui/util"
+)
+
+func TestInspectCert(t *testing.T) {
+	now := time.Now()
+	cert, key, err := createCert(&x509.Certificate{
+		Subject:   pkix.Name{CommonName: "example.com"},
+		NotBefore: now.Add(-time.Hour),
+		NotAfter:  now.Add(10*24*time.Hour + time.Hour),
+		DNSNames:  []string{"example.com", "*.example.com"},
+	}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherKey, err := createCert(&x509.Certificate{NotAfter: now.Add(time.Hour)}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	crtPath := filepath.Join(dir, "a.crt")
+	keyPath := filepath.Join(dir, "a.key")
+	otherKeyPath := filepath.Join(dir, "b.key")
+	writePem := func(path string, blockType string, der []byte) {
+		if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writePem(crtPath, "CERTIFICATE", cert.Raw)
+	keyDer, _ := x509.MarshalECPrivateKey(key)
+	writePem(keyPath, "EC PRIVATE KEY", keyDer)
+	otherKeyDer, _ := x509.MarshalECPrivateKey(otherKey)
+	writePem(otherKeyPath, "EC PRIVATE KEY", otherKeyDer)
+
+	info := inspectCert("hysteria2", crtPath, keyPath, []string{"www.example.com", "other.com"}, now)
+	if !info.KeyMatch || !info.SelfSigned || info.DaysLeft != 10 {
+		t.Fatalf("unexpected cert info: %+v", info)
+	}
+	if len(info.UncoveredHosts) != 1 || info.UncoveredHosts[0] != "other.com" {
+		t.Fatalf("uncovered hosts: %v", info.UncoveredHosts)
+	}
+	if problem := certProblem(info, 7, now); problem != "does not cover other.com" {
+		t.Fatalf("unexpected problem: %s", problem)
+	}
+	if problem := certProblem(info, 14, now); !strings.HasPrefix(problem, "expires in 10 days") ||
+		!strings.HasSuffix(problem, "; does not cover other.com") {
+		t.Fatalf("unexpected problem: %s", problem)
+	}
+	if problem := certProblem(info, 7, now.Add(11*24*time.Hour)); !strings.HasPrefix(problem, "expired at") {
+		t.Fatalf("unexpected problem: %s", problem)
+	}
+
+	// 即将到期和私钥不匹配同时报告
+	info = inspectCert("hysteria2", crtPath, otherKeyPath, nil, now)
+	problem := certProblem(info, 14, now)
+	if info.KeyMatch || !strings.HasPrefix(problem, "expires in 10 days") || !strings.Contains(problem, "does not match its key") {
+		t.Fatalf("key should not match: %s %+v", problem, info)
+	}
+	info = inspectCert("hysteria2", filepath.Join(dir, "missing.crt"), keyPath, nil, now)
+	if !strings.HasPrefix(certProblem(info, 7, now), "cannot be read") {
+		t.Fatalf("missing cert should be reported: %+v", info)
+	}
+}
+
+func TestReadKeyPkcs8(t *testing.T) {
+	_, key, err := createCert(&x509.Certificate{NotAfter: time.Now().Add(time.Hour)}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "a.key")
+	if err = os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	signer, err := readKey(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ecKey, ok := signer.(*ecdsa.PrivateKey); !ok || !ecKey.Equal(key) {
+		t.Fatalf("unexpected key: %T", signer)
+	}
+}
+
+func TestSetHysteria2ConfigTLSAcme(t *testing.T) {
+	config, err := dao.GetConfig("key = ?", constant.Hysteria2Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dao.UpdateConfig([]string{constant.Hysteria2Config}, map[string]interface{}{"value": *config.Value})
+
+	// 配置了 acme 时不修改配置
+	acmeConfig := "listen: :443\nacme:\n  domains:\n    - example.com\n"
+	if err = dao.UpdateConfig([]string{constant.Hysteria2Config}, map[string]interface{}{"value": acmeConfig}); err != nil {
+		t.Fatal(err)
+	}
+	if err = setHysteria2ConfigTLS("/a.crt", "/a.key"); !errors.Is(err, errHysteria2ConfigAcme) {
+		t.Fatalf("acme should be rejected: %v", err)
+	}
+	if _, err = GenerateManagedCert(constant.CertHysteria2Name, []string{"example.com"}, 1, false); !errors.Is(err, errHysteria2ConfigAcme) {
+		t.Fatalf("acme should be rejected before generating: %v", err)
+	}
+	current, err := dao.GetConfig("key = ?", constant.Hysteria2Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *current.Value != acmeConfig {
+		t.Fatalf("config should not change: %s", *current.Value)
+	}
+
+	if err = dao.UpdateConfig([]string{constant.Hysteria2Config}, map[string]interface{}{"value": "listen: :443\n"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = setHysteria2ConfigTLS("/a.crt", "/a.key"); err != nil {
+		t.Fatal(err)
+	}
+	hysteria2Config, err := GetHysteria2Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hysteria2Config.TLS == nil || *hysteria2Config.TLS.Cert != "/a.crt" || *hysteria2Config.TLS.Key != "/a.key" ||
+		hysteria2Config.Listen == nil || *hysteria2Config.Listen != ":443" {
+		t.Fatalf("unexpected config: %+v", hysteria2Config)
+	}
+}
+
+func TestRotateManagedCert(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err = os.Mkdir(filepath.Join(dir, constant.CertDir), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.Chdir(wd)
+		setServerCertConfig(t, "", "")
+	}()
+
+	isCaSigned := func(crtPath string) bool {
+		caCrtPath, _, _ := certPath(constant.CertCaName)
+		ca, err := readCert(caCrtPath)
+		if err != nil {
+			return false
+		}
+		cert, err := readCert(crtPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cert.CheckSignatureFrom(ca) == nil
+	}
+
+	managedCertVo, err := GenerateManagedCert(constant.CertPanelName, []string{"example.com"}, 30, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	managedCertVo, err = RotateManagedCert(constant.CertPanelName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if managedCertVo.CaCert == "" || !isCaSigned(managedCertVo.CrtPath) {
+		t.Fatal("rotated cert should be signed by the panel ca")
+	}
+
+	// 面板 CA 被替换后，旧 CA 签发的证书按自签重新生成
+	caCrtPath, caKeyPath, _ := certPath(constant.CertCaName)
+	_ = os.Remove(caCrtPath)
+	_ = os.Remove(caKeyPath)
+	managedCertVo, err = RotateManagedCert(constant.CertPanelName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if managedCertVo.CaCert != "" || util.Exists(caCrtPath) {
+		t.Fatal("rotated cert should be self-signed")
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"h-ui/model/bo"
 	"h-ui/model/constant"
 	"h-ui/model/entity"
+	"h-ui/util"
+	"net"
 	"strconv"
 	"strings"
 )
@@ -128,7 +130,7 @@ func GetHysteria2ApiPort() (int64, error) {
 		logrus.Errorf(errMsg)
 		return 0, errors.New(errMsg)
 	}
-	apiPort, err := strconv.ParseInt(strings.Split(*hysteria2Config.TrafficStats.Listen, ":")[1], 10, 64)
+	apiPort, err := strconv.ParseInt(util.SplitPort(*hysteria2Config.TrafficStats.Listen), 10, 64)
 	if err != nil {
 		errMsg := fmt.Sprintf("apiPort: %s is invalid", *hysteria2Config.TrafficStats.Listen)
 		logrus.Errorf(errMsg)
@@ -138,13 +140,14 @@ func GetHysteria2ApiPort() (int64, error) {
 }
 
 func GetPortAndCert() (int64, string, string, error) {
-	configs, err := dao.ListConfig("key in ?", []string{constant.HUIWebPort, constant.HUICrtPath, constant.HUIKeyPath})
+	configs, err := dao.ListConfig("key in ?", []string{constant.HUIWebPort, constant.HUICrtPath, constant.HUIKeyPath, constant.HUICertFromHysteria2})
 	if err != nil {
 		return 0, "", "", err
 	}
 	port := ""
 	crtPath := ""
 	keyPath := ""
+	certFromHysteria2 := ""
 	for _, config := range configs {
 		value := *config.Value
 		if *config.Key == constant.HUIWebPort {
@@ -153,6 +156,16 @@ func GetPortAndCert() (int64, string, string, error) {
 			crtPath = value
 		} else if *config.Key == constant.HUIKeyPath {
 			keyPath = value
+		} else if *config.Key == constant.HUICertFromHysteria2 {
+			certFromHysteria2 = value
+		}
+	}
+
+	// 没有单独配置面板证书时使用 hysteria2 的证书，包括 ACME 签发的证书
+	if crtPath == "" && keyPath == "" && certFromHysteria2 == "1" {
+		// 找不到证书时面板使用 HTTP，启动时记录日志
+		if acmePath, err := Hysteria2AcmePath(); err == nil {
+			crtPath, keyPath = acmePath.CrtPath, acmePath.KeyPath
 		}
 	}
 
@@ -182,5 +195,14 @@ func GetAuthHttpUrl() (string, error) {
 	if config.Value != nil && *config.Value != "/" && strings.HasPrefix(*config.Value, "/") {
 		webContext = *config.Value
 	}
-	return fmt.Sprintf("%s://127.0.0.1:%d%s/hui/hysteria2/auth", protocol, port, webContext), nil
+	// 面板只监听指定 IP 时使用该 IP 访问
+	host := "127.0.0.1"
+	bindAddress, _, err := GetServerListen()
+	if err != nil {
+		return "", err
+	}
+	if ip := net.ParseIP(bindAddress); ip != nil && !ip.IsUnspecified() {
+		host = ip.String()
+	}
+	return fmt.Sprintf("%s://%s%s/hui/hysteria2/auth", protocol, net.JoinHostPort(host, strconv.FormatInt(port, 10)), webContext), nil
 }
@@ -1,10 +1,12 @@
 package service
 
 import (
+	"fmt"
 	"github.com/sirupsen/logrus"
 	"h-ui/dao"
 	"h-ui/model/bo"
 	"h-ui/model/constant"
+	"h-ui/model/entity"
 	"h-ui/proxy"
 	"h-ui/util"
 	"strconv"
@@ -55,6 +57,9 @@ func CronResetTraffic() {
 		if err := dao.UpdateAccount(item, map[string]interface{}{"download": 0, "upload": 0}); err != nil {
 			continue
 		}
+		for _, id := range item {
+			EmitAccountEventById(constant.AccountEventTrafficReset, id, nil)
+		}
 	}
 }
 
@@ -85,10 +90,26 @@ func saveAccountTraffic(apiPort int64, jwtSecret string) {
 			wg.Add(1)
 			go func(userList map[string]bo.Hysteria2UserTraffic) {
 				defer wg.Done()
+				usernames := make([]string, 0, len(userList))
+				for username := range userList {
+					usernames = append(usernames, username)
+				}
+				// 记录更新前的账户，用于判断本次是否刚好超出配额
+				accounts := map[string]entity.Account{}
+				if list, err := dao.ListAccount("username in ?", usernames); err == nil {
+					for _, item := range list {
+						accounts[*item.Username] = item
+					}
+				}
 				for username, traffic := range userList {
-					if err = dao.UpdateAccountTraffic(username, int64(float64(traffic.Rx)*hysteria2TrafficTimeFloat), int64(float64(traffic.Tx)*hysteria2TrafficTimeFloat)); err != nil {
+					download := int64(float64(traffic.Rx) * hysteria2TrafficTimeFloat)
+					upload := int64(float64(traffic.Tx) * hysteria2TrafficTimeFloat)
+					if err := dao.UpdateAccountTraffic(username, download, upload); err != nil {
 						continue
 					}
+					if account, ok := accounts[username]; ok {
+						accountTrafficChanged(account, download+upload)
+					}
 				}
 			}(userList)
 		}
@@ -96,6 +117,21 @@ func saveAccountTraffic(apiPort int64, jwtSecret string) {
 	}
 }
 
+// accountTrafficChanged 本次流量使账户超出配额时通知并触发 webhook
+func accountTrafficChanged(account entity.Account, traffic int64) {
+	used := *account.Download + *account.Upload
+	// 与 Hysteria2Auth 一致：-1 不限流量，0 没有流量
+	if *account.Quota < 0 || used >= *account.Quota || used+traffic < *account.Quota {
+		return
+	}
+	text := fmt.Sprintf("%s has used up the traffic quota (%s/%s)",
+		*account.Username, util.FormatBytes(used+traffic), util.FormatBytes(*account.Quota))
+	if err := Notify(constant.NotifyEventQuota, text); err != nil && err != ErrNotifyNoChannel {
+		logrus.Warnf("notify quota err: %v", err)
+	}
+	EmitAccountEventById(constant.AccountEventQuotaExceeded, *account.Id, nil)
+}
+
 func kickAccount(apiPort int64, jwtSecret string) {
 	if !kickMutex.TryLock() {
 		return
@@ -120,7 +156,7 @@ func kickAccount(apiPort int64, jwtSecret string) {
 			go func(usernameList []string) {
 				defer wg.Done()
 				now := time.Now().UnixMilli()
-				accounts, err := dao.ListAccount("username in ? and (deleted = 1 or (quota > 0 and quota < download + upload)) or ? > expire_time or ? < kick_util_time", usernameList, now, now)
+				accounts, err := dao.ListAccount("username in ? and (deleted = 1 or (quota > 0 and quota < download + upload) or ? > expire_time or ? < kick_util_time)", usernameList, now, now)
 				if err != nil {
 					return
 				}
@@ -133,8 +169,27 @@ func kickAccount(apiPort int64, jwtSecret string) {
 				if err = proxy.NewHysteria2Api(apiPort).KickUsers(kickUsernames, jwtSecret); err != nil {
 					return
 				}
+				for _, item := range accounts {
+					reason := kickReason(item, now)
+					if reason == "expired" {
+						accountExpired(item)
+					}
+					EmitAccountEvent(constant.AccountEventKicked, item, map[string]interface{}{"reason": reason})
+				}
 			}(usernameList)
 		}
 		wg.Wait()
 	}
 }
+
+func kickReason(account entity.Account, now int64) string {
+	switch {
+	case *account.Deleted == 1:
+		return "disabled"
+	case *account.Quota > 0 && *account.Quota < *account.Download+*account.Upload:
+		return "quota_exceeded"
+	case now > *account.ExpireTime:
+		return "expired"
+	}
+	return "manual"
+}
@@ -61,11 +61,11 @@ func InitPortHopping() error {
 		return err
 	}
 	if *hysteria2ConfigPortHopping.Value != "" {
-		listen := strings.Split(*hysteria2Config.Listen, ":")
-		if len(listen) == 2 {
+		listenPort := util.SplitPort(*hysteria2Config.Listen)
+		if listenPort != "" {
 			portHoppings := strings.Split(*hysteria2ConfigPortHopping.Value, ",")
 			for _, item := range portHoppings {
-				if err := portForward(item, listen[1], Add); err != nil {
+				if err := portForward(item, listenPort, Add); err != nil {
 					return err
 				}
 			}
@@ -232,3 +232,26 @@ func iptablesRules(protocol string) ([]string, error) {
 	rules := strings.Split(output, "\n")
 	return rules, nil
 }
+
+// PortHoppingRuleExists 端口跳跃规则是否存在
+func PortHoppingRuleExists() (bool, error) {
+	var rules []string
+	var err error
+	switch netManager {
+	case "nft":
+		rules, err = nftRules()
+	case "iptables":
+		rules, err = iptablesRules("iptables")
+	default:
+		return false, errors.New("port hopping not supported on this system")
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, rule := range rules {
+		if strings.Contains(rule, Comment) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"errors"
+	"h-ui/dao"
+	"h-ui/model/constant"
+	"h-ui/model/vo"
+	"h-ui/proxy"
+)
+
+func Readiness() vo.ReadinessVo {
+	readinessVo := vo.ReadinessVo{Status: vo.HealthOk}
+	check := func(name string, err error) {
+		healthCheckVo := vo.HealthCheckVo{Name: name, Status: vo.HealthOk}
+		if err != nil {
+			healthCheckVo.Status = vo.HealthFail
+			healthCheckVo.Message = err.Error()
+			readinessVo.Status = vo.HealthFail
+		}
+		readinessVo.Checks = append(readinessVo.Checks, healthCheckVo)
+	}
+	skip := func(name string, message string) {
+		readinessVo.Checks = append(readinessVo.Checks, vo.HealthCheckVo{Name: name, Status: vo.HealthSkip, Message: message})
+	}
+
+	dbErr := dao.PingSqliteDB()
+	check("database", dbErr)
+	if dbErr != nil {
+		return readinessVo
+	}
+
+	hysteria2Enable, err := dao.GetConfig("key = ?", constant.Hysteria2Enable)
+	if err != nil || hysteria2Enable.Value == nil || *hysteria2Enable.Value != "1" {
+		skip("hysteria2", "hysteria2 is not enabled")
+		skip("trafficStats", "hysteria2 is not enabled")
+		skip("portHopping", "hysteria2 is not enabled")
+		return readinessVo
+	}
+
+	if Hysteria2IsRunning() {
+		check("hysteria2", nil)
+		check("trafficStats", pingTrafficStats())
+	} else {
+		check("hysteria2", errors.New("hysteria2 is not running"))
+		skip("trafficStats", "hysteria2 is not running")
+	}
+
+	portHopping, err := dao.GetConfig("key = ?", constant.Hysteria2ConfigPortHopping)
+	if err != nil || portHopping.Value == nil || *portHopping.Value == "" {
+		skip("portHopping", "port hopping is not configured")
+	} else {
+		exists, err := PortHoppingRuleExists()
+		if err == nil && !exists {
+			err = errors.New("port hopping rule not found")
+		}
+		check("portHopping", err)
+	}
+	return readinessVo
+}
+
+func pingTrafficStats() error {
+	apiPort, err := GetHysteria2ApiPort()
+	if err != nil {
+		return err
+	}
+	jwtSecretConfig, err := dao.GetConfig("key = ?", constant.JwtSecret)
+	if err != nil {
+		return err
+	}
+	_, err = proxy.NewHysteria2Api(apiPort).OnlineUsers(*jwtSecretConfig.Value)
+	return err
+}
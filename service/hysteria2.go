@@ -11,9 +11,21 @@ import (
 	"h-ui/proxy"
 	"h-ui/util"
 	"os"
+	"sync/atomic"
+	"time"
 )
 
+// hysteria2StartTime Hysteria2 进程启动时间的 UnixNano，0 表示未运行，OnExit 回调和请求会同时读写
+var hysteria2StartTime atomic.Int64
+
 func InitHysteria2() error {
+	proxy.NewHysteria2Instance().OnExit(func(err error) {
+		hysteria2StartTime.Store(0)
+		if err := Notify(constant.NotifyEventHysteria2Crash, fmt.Sprintf("Hysteria2 exited unexpectedly: %v", err)); err != nil {
+			logrus.Warnf("notify hysteria2 crash err: %v", err)
+		}
+	})
+
 	if !util.Exists(util.GetHysteria2BinPath()) {
 		if err := util.DownloadHysteria2(""); err != nil {
 			logrus.Errorf("download hysteria2 bin err: %v", err)
@@ -82,14 +94,31 @@ func StartHysteria2() error {
 	if err := setHysteria2ConfigYAML(); err != nil {
 		return err
 	}
+	running := Hysteria2IsRunning()
 	if err := proxy.NewHysteria2Instance().StartHysteria2(); err != nil {
 		return err
 	}
+	if !running {
+		hysteria2StartTime.Store(time.Now().UnixNano())
+	}
 	return nil
 }
 
 func StopHysteria2() error {
-	return proxy.NewHysteria2Instance().StopHysteria2()
+	if err := proxy.NewHysteria2Instance().StopHysteria2(); err != nil {
+		return err
+	}
+	hysteria2StartTime.Store(0)
+	return nil
+}
+
+// Hysteria2Uptime Hysteria2 当前运行时长，未运行时为 0
+func Hysteria2Uptime() time.Duration {
+	startTime := hysteria2StartTime.Load()
+	if !Hysteria2IsRunning() || startTime == 0 {
+		return 0
+	}
+	return time.Since(time.Unix(0, startTime))
 }
 
 func RestartHysteria2() error {
@@ -99,6 +128,7 @@ func RestartHysteria2() error {
 	if err := StartHysteria2(); err != nil {
 		return err
 	}
+	metricsHysteria2Restart()
 	return nil
 }
 
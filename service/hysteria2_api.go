@@ -3,10 +3,11 @@ package service
 import (
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"github.com/sirupsen/logrus"
 	"h-ui/dao"
 	"h-ui/model/bo"
 	"h-ui/model/constant"
+	"h-ui/model/entity"
 	"h-ui/proxy"
 	"net/url"
 	"strings"
@@ -15,25 +16,50 @@ import (
 
 func Hysteria2Auth(conPass string) (int64, string, error) {
 	if !Hysteria2IsRunning() {
+		MetricsAuth("reject", "not_running")
 		return 0, "", errors.New("hysteria2 is not running")
 	}
 
 	now := time.Now().UnixMilli()
-	account, err := dao.GetAccount("con_pass = ? and deleted = 0 and (quota < 0 or quota > download + upload) and ? < expire_time and ? > kick_util_time", conPass, now, now)
+	account, err := dao.GetAccount("con_pass = ?", conPass)
 	if err != nil {
+		MetricsAuth("reject", "not_found")
 		return 0, "", err
 	}
+	if *account.Deleted != 0 {
+		MetricsAuth("reject", "disabled")
+		return 0, "", errors.New(constant.WrongPassword)
+	}
+	if *account.Quota >= 0 && *account.Quota <= *account.Download+*account.Upload {
+		MetricsAuth("reject", "quota_exceeded")
+		return 0, "", errors.New(constant.WrongPassword)
+	}
+	if now >= *account.ExpireTime {
+		MetricsAuth("reject", "expired")
+		return 0, "", errors.New(constant.WrongPassword)
+	}
+	if now <= *account.KickUtilTime {
+		MetricsAuth("reject", "kicked")
+		return 0, "", errors.New(constant.WrongPassword)
+	}
 
 	// 限制设备数
 	onlineUsers, err := Hysteria2Online()
 	if err != nil {
+		MetricsAuth("reject", "error")
 		return 0, "", err
 	}
 	device, exist := onlineUsers[*account.Username]
 	if exist && *account.DeviceNo <= device {
+		MetricsAuth("reject", "device_limited")
 		return 0, "", errors.New("device limited")
 	}
 
+	MetricsAuth("accept", "ok")
+	// 并发认证时只有一个请求能更新成功，避免重复触发首次连接事件
+	if first, err := dao.UpdateAccountFirstConAt(*account.Id, now); err == nil && first {
+		go EmitAccountEvent(constant.AccountEventFirstConnection, account, nil)
+	}
 	return *account.Id, *account.Username, nil
 }
 
@@ -57,6 +83,10 @@ func Hysteria2Online() (map[string]int64, error) {
 }
 
 func Hysteria2Kick(ids []int64, kickUtilTime int64) error {
+	return hysteria2Kick(ids, kickUtilTime, "manual")
+}
+
+func hysteria2Kick(ids []int64, kickUtilTime int64, reason string) error {
 	if !Hysteria2IsRunning() {
 		return errors.New("hysteria2 is not running")
 	}
@@ -83,6 +113,14 @@ func Hysteria2Kick(ids []int64, kickUtilTime int64) error {
 	if err = proxy.NewHysteria2Api(apiPort).KickUsers(keys, *jwtSecretConfig.Value); err != nil {
 		return err
 	}
+	go func() {
+		for _, item := range accounts {
+			EmitAccountEvent(constant.AccountEventKicked, item, map[string]interface{}{
+				"reason":       reason,
+				"kickUtilTime": kickUtilTime,
+			})
+		}
+	}()
 	return nil
 }
 
@@ -91,6 +129,13 @@ func Hysteria2SubscribeUrl(accountId int64, protocol string, host string) (strin
 	if err != nil {
 		return "", err
 	}
+	publicUrl, err := dao.GetConfig("key = ?", constant.HUIPublicUrl)
+	if err != nil {
+		return "", err
+	}
+	if *publicUrl.Value != "" {
+		return fmt.Sprintf("%s/hui/%s", strings.TrimSuffix(*publicUrl.Value, "/"), url.QueryEscape(*account.ConPass)), nil
+	}
 	config, err := dao.GetConfig("key = ?", constant.HUIWebContext)
 	if err != nil {
 		return "", err
@@ -102,181 +147,237 @@ func Hysteria2SubscribeUrl(accountId int64, protocol string, host string) (strin
 	return fmt.Sprintf("%s//%s%s/hui/%s", protocol, host, webContext, url.QueryEscape(*account.ConPass)), nil
 }
 
-func Hysteria2Subscribe(conPass string, clientType string, host string) (string, string, error) {
-	hysteria2Config, err := GetHysteria2Config()
+func Hysteria2Subscribe(conPass string, clientType string, protocol string, host string) (string, string, error) {
+	account, err := dao.GetAccount("con_pass = ?", conPass)
 	if err != nil {
 		return "", "", err
 	}
-	if hysteria2Config.Listen == nil || *hysteria2Config.Listen == "" {
-		return "", "", errors.New("hysteria2 config is empty")
+	clashExtension, err := GetConfig(constant.ClashExtension)
+	if err != nil {
+		return "", "", err
 	}
+	return hysteria2Subscribe(account, clientType, protocol, host, *clashExtension.Value)
+}
 
-	account, err := dao.GetAccount("con_pass = ?", conPass)
+// ClashPreview 使用指定模板渲染账户的 Clash 订阅，模板为空时使用已保存的模板
+func ClashPreview(accountId int64, clientType string, template *string, protocol string, host string) (string, error) {
+	account, err := dao.GetAccount("id = ?", accountId)
 	if err != nil {
-		return "", "", err
+		return "", err
+	}
+	if template == nil {
+		clashExtension, err := GetConfig(constant.ClashExtension)
+		if err != nil {
+			return "", err
+		}
+		template = clashExtension.Value
+	}
+	// 预览时直接返回模板错误，不使用订阅时的兼容处理
+	if err = ValidClashTemplate(*template); err != nil {
+		return "", err
 	}
+	_, configStr, err := hysteria2Subscribe(account, clientType, protocol, host, *template)
+	return configStr, err
+}
 
-	hysteria2Name := "hysteria2"
-	hysteria2ConfigRemark, err := dao.GetConfig("key = ?", constant.Hysteria2ConfigRemark)
+func hysteria2Subscribe(account entity.Account, clientType string, protocol string, host string, clashTemplate string) (string, string, error) {
+	local, err := localHysteria2Node(account, host)
 	if err != nil {
 		return "", "", err
 	}
-	if *hysteria2ConfigRemark.Value != "" {
-		hysteria2Name = *hysteria2ConfigRemark.Value
+	if clientType == constant.Hysteria2 {
+		// 官方客户端只能连接一个服务器，使用本机节点且不添加信息节点
+		socks5, http, err := getHysteria2ClientListen()
+		if err != nil {
+			return "", "", err
+		}
+		configStr, err := hysteria2ClientSubscribe(local, socks5, http)
+		if err != nil {
+			return "", "", err
+		}
+		return subscribeUserInfo(account), configStr, nil
 	}
-
-	hysteria2ConfigPortHopping, err := dao.GetConfig("key = ?", constant.Hysteria2ConfigPortHopping)
+	nodes, err := subscribeNodes(local)
 	if err != nil {
 		return "", "", err
 	}
+	info, err := getSubscribeInfo()
+	if err != nil {
+		return "", "", err
+	}
+	nodes = subscribeInfoNodes(account, nodes, info, time.Now())
 
 	userInfo := ""
 	configStr := ""
 	if clientType == constant.Shadowrocket || clientType == constant.Clash {
-		userInfo = fmt.Sprintf("upload=%d; download=%d; total=%d; expire=%d",
-			*account.Upload,
-			*account.Download,
-			*account.Quota,
-			*account.ExpireTime/1000)
-
-		hysteria2 := bo.Hysteria2{
-			Name:     hysteria2Name,
-			Type:     "hysteria2",
-			Server:   strings.Split(host, ":")[0],
-			Port:     strings.Split(*hysteria2Config.Listen, ":")[1],
-			Ports:    *hysteria2ConfigPortHopping.Value,
-			Password: conPass,
-		}
-
-		if hysteria2Config.Bandwidth != nil {
-			if hysteria2Config.Bandwidth.Up != nil &&
-				*hysteria2Config.Bandwidth.Up != "" {
-				hysteria2.Up = *hysteria2Config.Bandwidth.Up
-			}
-			if hysteria2Config.Bandwidth.Down != nil &&
-				*hysteria2Config.Bandwidth.Down != "" {
-				hysteria2.Down = *hysteria2Config.Bandwidth.Down
-			}
-		}
-
-		if hysteria2Config.Obfs != nil &&
-			hysteria2Config.Obfs.Type != nil &&
-			*hysteria2Config.Obfs.Type == "salamander" &&
-			hysteria2Config.Obfs.Salamander != nil &&
-			hysteria2Config.Obfs.Salamander.Password != nil &&
-			*hysteria2Config.Obfs.Salamander.Password != "" {
-			if clientType == constant.Shadowrocket {
-				hysteria2.Obfs = *hysteria2Config.Obfs.Salamander.Password
-			} else {
-				hysteria2.Obfs = "salamander"
-				hysteria2.ObfsPassword = *hysteria2Config.Obfs.Salamander.Password
-			}
+		userInfo = subscribeUserInfo(account)
+		configStr, err = clashSubscribe(nodes, clientType == constant.Shadowrocket)
+		if err != nil {
+			return "", "", err
 		}
-
-		if hysteria2Config.ACME != nil &&
-			hysteria2Config.ACME.Domains != nil &&
-			len(hysteria2Config.ACME.Domains) > 0 {
-			hysteria2.Sni = hysteria2Config.ACME.Domains[0]
+		if clientType == constant.Clash {
+			configStr = applyClashTemplate(configStr, clashTemplate, nodes)
 		}
-
-		hysteria2.SkipCertVerify = false
-
-		proxyGroup := bo.ProxyGroup{
-			Name:    "PROXY",
-			Type:    "select",
-			Proxies: []string{hysteria2Name},
+	} else if clientType == constant.NekoBox || clientType == constant.SingBox {
+		userInfo = subscribeUserInfo(account)
+		configStr, err = singBoxSubscribe(nodes)
+		if err != nil {
+			return "", "", err
 		}
-
-		clashConfig := bo.ClashConfig{
-			ProxyGroups: []bo.ProxyGroup{
-				proxyGroup,
-			},
-			Proxies: []interface{}{hysteria2},
+	} else if clientType == constant.Stash {
+		userInfo = subscribeUserInfo(account)
+		configStr, err = stashSubscribe(nodes)
+		if err != nil {
+			return "", "", err
 		}
-		clashConfigYaml, err := yaml.Marshal(&clashConfig)
+		configStr = applyClashTemplate(configStr, clashTemplate, nodes)
+	} else if clientType == constant.Surge {
+		userInfo = subscribeUserInfo(account)
+		subscribeUrl, err := Hysteria2SubscribeUrl(*account.Id, protocol, host)
 		if err != nil {
 			return "", "", err
 		}
-		configStr = string(clashConfigYaml)
-		if clientType == constant.Clash {
-			clashExtension, err := GetConfig(constant.ClashExtension)
-			if err != nil {
-				return "", "", err
-			}
-			if clashExtension.Value != nil && *clashExtension.Value != "" {
-				configStr = fmt.Sprintf("%s%s", configStr, *clashExtension.Value)
-			}
+		configStr, err = surgeSubscribe(nodes, subscribeUrl)
+		if err != nil {
+			return "", "", err
 		}
-	} else if clientType == constant.V2rayN {
-		hysteria2Url, err := Hysteria2Url(*account.Id, strings.Split(host, ":")[0])
+	} else if clientType == constant.Loon {
+		userInfo = subscribeUserInfo(account)
+		configStr, err = loonSubscribe(nodes)
 		if err != nil {
 			return "", "", err
 		}
-		configStr = hysteria2Url
+	} else if clientType == constant.QuantumultX {
+		userInfo = subscribeUserInfo(account)
+		configStr = quantumultXSubscribe(nodes)
+	} else if clientType == constant.V2rayN {
+		configStr = urlSubscribe(nodes)
 	}
 
 	return userInfo, configStr, nil
 }
 
-func Hysteria2Url(accountId int64, hostname string) (string, error) {
+// applyClashTemplate 合并 CLASH_EXTENSION 模板，无法解析时按旧版本直接追加到末尾
+func applyClashTemplate(configStr string, template string, nodes []bo.Hysteria2Node) string {
+	if strings.TrimSpace(template) == "" {
+		return configStr
+	}
+	var proxyNames []string
+	for _, node := range nodes {
+		proxyNames = append(proxyNames, node.Name)
+	}
+	rendered, err := renderClashTemplate(configStr, template, proxyNames)
+	if err != nil {
+		logrus.Warnf("render clash template err: %v", err)
+		return fmt.Sprintf("%s%s", configStr, template)
+	}
+	return rendered
+}
+
+// localHysteria2Node 本机节点，地址使用订阅请求的 Host
+func localHysteria2Node(account entity.Account, host string) (bo.Hysteria2Node, error) {
 	hysteria2Config, err := GetHysteria2Config()
 	if err != nil {
-		return "", err
+		return bo.Hysteria2Node{}, err
 	}
 	if hysteria2Config.Listen == nil || *hysteria2Config.Listen == "" {
-		return "", errors.New("hysteria2 config is empty")
+		return bo.Hysteria2Node{}, errors.New("hysteria2 config is empty")
 	}
 
-	account, err := dao.GetAccount("id = ?", accountId)
+	hysteria2Name := "hysteria2"
+	hysteria2ConfigRemark, err := dao.GetConfig("key = ?", constant.Hysteria2ConfigRemark)
 	if err != nil {
-		return "", err
+		return bo.Hysteria2Node{}, err
 	}
-
-	urlConfig := ""
-	if hysteria2Config.Obfs != nil &&
-		hysteria2Config.Obfs.Type != nil &&
-		*hysteria2Config.Obfs.Type == "salamander" &&
-		hysteria2Config.Obfs.Salamander != nil &&
-		hysteria2Config.Obfs.Salamander.Password != nil &&
-		*hysteria2Config.Obfs.Salamander.Password != "" {
-		urlConfig += fmt.Sprintf("&obfs=salamander&obfs-password=%s", *hysteria2Config.Obfs.Salamander.Password)
+	if *hysteria2ConfigRemark.Value != "" {
+		hysteria2Name = *hysteria2ConfigRemark.Value
 	}
 
-	if hysteria2Config.ACME != nil &&
-		hysteria2Config.ACME.Domains != nil &&
-		len(hysteria2Config.ACME.Domains) > 0 {
-		urlConfig += fmt.Sprintf("&sni=%s", hysteria2Config.ACME.Domains[0])
-		// shadowrocket
-		urlConfig += fmt.Sprintf("&peer=%s", hysteria2Config.ACME.Domains[0])
+	configs, err := dao.ListConfig("key in ?", []string{
+		constant.Hysteria2ConfigPortHopping,
+		constant.SubscribeServer,
+		constant.SubscribePort})
+	if err != nil {
+		return bo.Hysteria2Node{}, err
+	}
+	var portHopping, subscribeServer, subscribePort string
+	for _, item := range configs {
+		switch *item.Key {
+		case constant.Hysteria2ConfigPortHopping:
+			portHopping = *item.Value
+		case constant.SubscribeServer:
+			subscribeServer = *item.Value
+		case constant.SubscribePort:
+			subscribePort = *item.Value
+		}
 	}
-
-	urlConfig += "&insecure=0"
-
-	if hysteria2Config.Bandwidth != nil &&
-		hysteria2Config.Bandwidth.Down != nil &&
-		*hysteria2Config.Bandwidth.Down != "" {
-		// shadowrocket
-		urlConfig += fmt.Sprintf("&downmbps=%s", url.PathEscape(*hysteria2Config.Bandwidth.Down))
+	// 配置了公网地址时不使用请求的 Host，面板和 hysteria2 可能不在同一个域名
+	if subscribeServer != "" {
+		host = subscribeServer
 	}
+	node := hysteria2Node(hysteria2Config, account, hysteria2Name, portHopping, host)
+	// 面板生成的证书客户端无法验证，跳过验证并使用指纹固定证书
+	if hysteria2Config.TLS != nil && hysteria2Config.TLS.Cert != nil {
+		if pin := managedCertPin(*hysteria2Config.TLS.Cert); pin != "" {
+			node.PinSHA256 = pin
+			node.Insecure = true
+		}
+	}
+	if subscribePort != "" {
+		node.Port = subscribePort
+	}
+	if node.Port == "" {
+		return bo.Hysteria2Node{}, errors.New("hysteria2 listen port is empty")
+	}
+	return node, nil
+}
 
-	hysteria2ConfigPortHopping, err := dao.GetConfig("key = ?", constant.Hysteria2ConfigPortHopping)
+func Hysteria2Url(accountId int64, hostname string) (string, error) {
+	account, err := dao.GetAccount("id = ?", accountId)
 	if err != nil {
 		return "", err
 	}
-	if *hysteria2ConfigPortHopping.Value != "" {
-		// shadowrocket
-		urlConfig += fmt.Sprintf("&mport=%s", *hysteria2ConfigPortHopping.Value)
+	node, err := localHysteria2Node(account, hostname)
+	if err != nil {
+		return "", err
 	}
-
+	// 未设置备注时分享链接不带节点名
 	hysteria2ConfigRemark, err := dao.GetConfig("key = ?", constant.Hysteria2ConfigRemark)
 	if err != nil {
 		return "", err
 	}
-	if *hysteria2ConfigRemark.Value != "" {
-		urlConfig += fmt.Sprintf("#%s", *hysteria2ConfigRemark.Value)
+	if *hysteria2ConfigRemark.Value == "" {
+		node.Name = ""
+	}
+	return hysteria2NodeUrl(node), nil
+}
+
+// Hysteria2ClientConfig 账户的官方客户端配置，nodeId 为空时使用本机节点，socks5 和 http 为空时使用默认监听地址
+func Hysteria2ClientConfig(accountId int64, hostname string, nodeId *int64, socks5 *string, http *string) (string, error) {
+	account, err := dao.GetAccount("id = ?", accountId)
+	if err != nil {
+		return "", err
+	}
+	node, err := localHysteria2Node(account, hostname)
+	if err != nil {
+		return "", err
+	}
+	if nodeId != nil {
+		registered, err := dao.GetNode("id = ?", *nodeId)
+		if err != nil {
+			return "", err
+		}
+		node = registeredHysteria2Node(registered, node.Password)
+	}
+	defaultSocks5, defaultHttp, err := getHysteria2ClientListen()
+	if err != nil {
+		return "", err
+	}
+	if socks5 == nil {
+		socks5 = &defaultSocks5
 	}
-	if urlConfig != "" {
-		urlConfig = "/?" + strings.TrimPrefix(urlConfig, "&")
+	if http == nil {
+		http = &defaultHttp
 	}
-	return fmt.Sprintf("hysteria2://%s@%s%s", *account.ConPass, hostname, *hysteria2Config.Listen) + urlConfig, nil
+	return hysteria2ClientSubscribe(node, *socks5, *http)
 }
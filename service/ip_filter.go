@@ -0,0 +1,238 @@
+package service
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"h-ui/dao"
+	"h-ui/model/constant"
+	"h-ui/util"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ipFilterKeys 各范围的 IP 白名单、IP 黑名单、国家白名单和国家黑名单
+var ipFilterKeys = map[string][4]string{
+	constant.IPFilterAdmin: {constant.AdminAllowIps, constant.AdminDenyIps,
+		constant.AdminAllowCountries, constant.AdminDenyCountries},
+	constant.IPFilterPublic: {constant.PublicAllowIps, constant.PublicDenyIps,
+		constant.PublicAllowCountries, constant.PublicDenyCountries},
+}
+
+var countryRegexp = regexp.MustCompile("^[A-Z]{2}$")
+
+type ipFilterRule struct {
+	allowIps       []string
+	denyIps        []string
+	allowCountries []string
+	denyCountries  []string
+	geoIPDBPath    string
+}
+
+// loadedGeoIPDB 记录文件修改时间，文件更新后重新加载，加载失败时记录错误
+type loadedGeoIPDB struct {
+	path      string
+	modTime   time.Time
+	checkedAt time.Time
+	db        *util.MMDB
+	err       error
+}
+
+var geoIPDB *loadedGeoIPDB
+var geoIPDBMutex sync.Mutex
+
+func splitFilterList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getIPFilterRule 读取范围的名单，updates 中的值优先，用于保存前检查
+func getIPFilterRule(scope string, updates map[string]string) (ipFilterRule, error) {
+	keys := ipFilterKeys[scope]
+	configs, err := dao.ListConfig("key in ?", []string{keys[0], keys[1], keys[2], keys[3], constant.GeoIPDBPath})
+	if err != nil {
+		return ipFilterRule{}, err
+	}
+	values := map[string]string{}
+	for _, item := range configs {
+		values[*item.Key] = *item.Value
+	}
+	for key, value := range updates {
+		values[key] = value
+	}
+	return ipFilterRule{
+		allowIps:       splitFilterList(values[keys[0]]),
+		denyIps:        splitFilterList(values[keys[1]]),
+		allowCountries: splitFilterList(strings.ToUpper(values[keys[2]])),
+		denyCountries:  splitFilterList(strings.ToUpper(values[keys[3]])),
+		geoIPDBPath:    values[constant.GeoIPDBPath],
+	}, nil
+}
+
+// geoIPCountry 查询 IP 的国家，数据库文件每分钟最多检查一次是否更新
+func geoIPCountry(path string, ip net.IP) (string, error) {
+	geoIPDBMutex.Lock()
+	defer geoIPDBMutex.Unlock()
+
+	now := time.Now()
+	if geoIPDB == nil || geoIPDB.path != path || now.Sub(geoIPDB.checkedAt) > time.Minute {
+		geoIPDB = loadGeoIPDB(path, geoIPDB)
+		geoIPDB.checkedAt = now
+	}
+	if geoIPDB.err != nil {
+		return "", geoIPDB.err
+	}
+	return geoIPDB.db.Country(ip)
+}
+
+// loadGeoIPDB 文件没有变化时沿用 current
+func loadGeoIPDB(path string, current *loadedGeoIPDB) *loadedGeoIPDB {
+	info, err := os.Stat(path)
+	if err == nil && current != nil && current.path == path && current.err == nil && info.ModTime().Equal(current.modTime) {
+		return current
+	}
+	var db *util.MMDB
+	if err == nil {
+		db, err = util.OpenMMDB(path)
+	}
+	if err != nil {
+		logrus.Warnf("load geoip db %s err: %v", path, err)
+		return &loadedGeoIPDB{path: path, err: err}
+	}
+	logrus.Infof("geoip db loaded: %s", path)
+	return &loadedGeoIPDB{path: path, modTime: info.ModTime(), db: db}
+}
+
+// empty 没有配置任何名单
+// hasAllowList 是否有生效的白名单，国家白名单需要 GeoIP 数据库才会生效
+func (rule ipFilterRule) hasAllowList() bool {
+	return len(rule.allowIps) > 0 || (rule.geoIPDBPath != "" && len(rule.allowCountries) > 0)
+}
+
+// allow 黑名单优先于白名单。
+// 国家名单需要 GeoIP 数据库，数据库不可用或查不到国家（例如内网地址）时不按国家过滤
+func (rule ipFilterRule) allow(ip net.IP) bool {
+	if util.IPInCIDRs(ip, rule.denyIps) {
+		return false
+	}
+	if len(rule.allowIps) > 0 && !util.IPInCIDRs(ip, rule.allowIps) {
+		return false
+	}
+	if rule.geoIPDBPath == "" || (len(rule.allowCountries) == 0 && len(rule.denyCountries) == 0) {
+		return true
+	}
+	country, err := geoIPCountry(rule.geoIPDBPath, ip)
+	if err != nil {
+		return true
+	}
+	if country == "" {
+		return true
+	}
+	if util.ArrContain(rule.denyCountries, country) {
+		return false
+	}
+	return len(rule.allowCountries) == 0 || util.ArrContain(rule.allowCountries, country)
+}
+
+// IPFilterAllow 客户端 IP 是否允许访问该范围的接口
+func IPFilterAllow(scope string, clientIP string) bool {
+	ip := net.ParseIP(clientIP)
+	if ip == nil {
+		return false
+	}
+	rule, err := getIPFilterRule(scope, nil)
+	if err != nil {
+		// 读取配置失败时不拦截，避免面板无法访问
+		return true
+	}
+	return rule.allow(ip)
+}
+
+// ValidIPFilterClient 保存名单前检查是否会拦截当前管理员，管理员需要同时能访问登录接口和管理接口。
+// 当前 IP 是本机地址时，可能是反向代理不在可信代理中，白名单会拦截所有真实客户端，只允许保存黑名单
+func ValidIPFilterClient(updates map[string]string, clientIP string) error {
+	ip := net.ParseIP(clientIP)
+	if ip == nil {
+		return nil
+	}
+	for _, scope := range []string{constant.IPFilterAdmin, constant.IPFilterPublic} {
+		changed := updates[constant.GeoIPDBPath] != ""
+		for _, key := range ipFilterKeys[scope] {
+			if _, ok := updates[key]; ok {
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		rule, err := getIPFilterRule(scope, updates)
+		if err != nil {
+			return err
+		}
+		if ip.IsLoopback() && rule.hasAllowList() {
+			return fmt.Errorf("%s ip filter cannot apply: your ip is %s, add the reverse proxy to %s first",
+				scope, clientIP, constant.HUITrustedProxies)
+		}
+		if !rule.allow(ip) {
+			return fmt.Errorf("%s ip filter would block your ip: %s", scope, clientIP)
+		}
+	}
+	return nil
+}
+
+// ValidIPFilterIps 校验逗号分隔的 IP 或 CIDR
+func ValidIPFilterIps(key string, value string) error {
+	for _, item := range splitFilterList(value) {
+		if _, _, err := net.ParseCIDR(item); err == nil {
+			continue
+		}
+		if net.ParseIP(item) == nil {
+			return fmt.Errorf("%s: %s is invalid", key, item)
+		}
+	}
+	return nil
+}
+
+// ValidIPFilterCountries 校验逗号分隔的两位国家代码，例如 CN,US
+func ValidIPFilterCountries(key string, value string) error {
+	for _, item := range splitFilterList(strings.ToUpper(value)) {
+		if !countryRegexp.MatchString(item) {
+			return fmt.Errorf("%s: %s is invalid", key, item)
+		}
+	}
+	return nil
+}
+
+// ValidGeoIPDB 校验 GeoIP 数据库文件可以解析
+func ValidGeoIPDB(path string) error {
+	if path == "" {
+		return nil
+	}
+	if _, err := util.OpenMMDB(path); err != nil {
+		return fmt.Errorf("geoip db: %s is invalid: %v", path, err)
+	}
+	return nil
+}
+
+// UserAgentBlocked User-Agent 是否包含 key 配置的关键字，不区分大小写
+func UserAgentBlocked(key string, userAgent string) bool {
+	config, err := dao.GetConfig("key = ?", key)
+	if err != nil {
+		return false
+	}
+	userAgent = strings.ToLower(userAgent)
+	for _, item := range splitFilterList(strings.ToLower(*config.Value)) {
+		if strings.Contains(userAgent, item) {
+			return true
+		}
+	}
+	return false
+}
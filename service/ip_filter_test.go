@@ -0,0 +1,100 @@
+package service
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+
+	"h-ui/model/constant"
+)
+
+// testGeoIPDB 只包含 1.0.0.0/8，国家为 AU
+var testGeoIPDB = filepath.Join("testdata", "geoip", "country.mmdb")
+
+func TestIPFilterRule(t *testing.T) {
+	rule := ipFilterRule{
+		allowIps: []string{"10.0.0.0/8", "192.0.2.1"},
+		denyIps:  []string{"10.1.0.0/16"},
+	}
+	cases := map[string]bool{
+		"127.0.0.1":   false,
+		"10.2.3.4":    true,
+		"192.0.2.1":   true,
+		"10.1.2.3":    false,
+		"192.0.2.2":   false,
+		"2001:db8::1": false,
+	}
+	for ip, expected := range cases {
+		if actual := rule.allow(net.ParseIP(ip)); actual != expected {
+			t.Errorf("%s: expected %v, got %v", ip, expected, actual)
+		}
+	}
+	// 没有 GeoIP 数据库时国家名单不生效
+	rule = ipFilterRule{allowCountries: []string{"US"}}
+	if !rule.allow(net.ParseIP("192.0.2.2")) {
+		t.Error("expected allowed without geoip db")
+	}
+}
+
+func TestIPFilterCountry(t *testing.T) {
+	country, err := geoIPCountry(testGeoIPDB, net.ParseIP("1.2.3.4"))
+	if err != nil || country != "AU" {
+		t.Fatalf("expected AU, got %q %v", country, err)
+	}
+	if country, err = geoIPCountry(testGeoIPDB, net.ParseIP("2.0.0.1")); err != nil || country != "" {
+		t.Fatalf("expected no country, got %q %v", country, err)
+	}
+
+	deny := ipFilterRule{denyCountries: []string{"AU"}, geoIPDBPath: testGeoIPDB}
+	allow := ipFilterRule{allowCountries: []string{"US"}, geoIPDBPath: testGeoIPDB}
+	cases := []struct {
+		rule     ipFilterRule
+		ip       string
+		expected bool
+	}{
+		{deny, "1.2.3.4", false},
+		{deny, "2.0.0.1", true},
+		{allow, "1.2.3.4", false},
+		// 查不到国家的地址不按国家过滤
+		{allow, "2.0.0.1", true},
+	}
+	for _, item := range cases {
+		if actual := item.rule.allow(net.ParseIP(item.ip)); actual != item.expected {
+			t.Errorf("%+v %s: expected %v, got %v", item.rule, item.ip, item.expected, actual)
+		}
+	}
+}
+
+func TestValidIPFilterClient(t *testing.T) {
+	if err := ValidIPFilterClient(map[string]string{constant.AdminAllowIps: "10.0.0.0/8"}, "192.0.2.1"); err == nil {
+		t.Error("expected admin allow list to lock out 192.0.2.1")
+	}
+	if err := ValidIPFilterClient(map[string]string{constant.PublicDenyIps: "192.0.2.0/24"}, "192.0.2.1"); err == nil {
+		t.Error("expected public deny list to lock out 192.0.2.1")
+	}
+	if err := ValidIPFilterClient(map[string]string{
+		constant.GeoIPDBPath:        testGeoIPDB,
+		constant.AdminDenyCountries: "AU"}, "1.2.3.4"); err == nil {
+		t.Error("expected country deny list to lock out 1.2.3.4")
+	}
+	if err := ValidIPFilterClient(map[string]string{constant.AdminAllowIps: "198.51.100.0/24,127.0.0.1"}, "127.0.0.1"); err == nil {
+		t.Error("expected allow list from a loopback client to be refused")
+	}
+	if err := ValidIPFilterClient(map[string]string{
+		constant.GeoIPDBPath:          testGeoIPDB,
+		constant.PublicAllowCountries: "AU"}, "127.0.0.1"); err == nil {
+		t.Error("expected country allow list from a loopback client to be refused")
+	}
+	// 黑名单不会拦截本机的管理员，通过 SSH 隧道访问时也可以保存
+	if err := ValidIPFilterClient(map[string]string{
+		constant.AdminDenyIps:       "198.51.100.0/24",
+		constant.AdminDenyCountries: "AU"}, "127.0.0.1"); err != nil {
+		t.Error(err)
+	}
+	if err := ValidIPFilterClient(map[string]string{constant.AdminDenyIps: "127.0.0.0/8"}, "127.0.0.1"); err == nil {
+		t.Error("expected deny list to lock out 127.0.0.1")
+	}
+	if err := ValidIPFilterClient(map[string]string{constant.AdminAllowIps: "192.0.2.0/24"}, "192.0.2.1"); err != nil {
+		t.Error(err)
+	}
+}
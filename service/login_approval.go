@@ -0,0 +1,154 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/sirupsen/logrus"
+	"h-ui/dao"
+	"h-ui/model/constant"
+	"h-ui/model/dto"
+	"h-ui/model/entity"
+	"h-ui/util"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const loginApprovalTimeout = 2 * time.Minute
+
+type loginApproval struct {
+	username string
+	ip       string
+	token    string
+	status   string
+	done     chan struct{}
+}
+
+var loginApprovalMutex sync.Mutex
+var loginApprovals = map[string]*loginApproval{}
+
+// requestLoginApproval 创建待审批的登录，token 只有审批通过后才会返回给前端
+func requestLoginApproval(username string, ip string, token string) (string, error) {
+	chatIdConfig, err := dao.GetConfig("key = ?", constant.TelegramChatId)
+	if err != nil {
+		return "", err
+	}
+	chatId, err := strconv.ParseInt(*chatIdConfig.Value, 10, 64)
+	if err != nil {
+		return "", errors.New("telegram chatId is invalid")
+	}
+	pendingId, err := util.RandomString(32)
+	if err != nil {
+		return "", err
+	}
+
+	text := fmt.Sprintf("【H UI】\nLogin request\nUsername: %s\nIP: %s\nTime: %s\nExpires in %d minutes",
+		username, ip, time.Now().Format("2006-01-02 15:04:05"), int(loginApprovalTimeout.Minutes()))
+	message := tgbotapi.NewMessage(chatId, text)
+	message.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("Approve", fmt.Sprintf("login|%s|%s", pendingId, constant.LoginApproved)),
+		tgbotapi.NewInlineKeyboardButtonData("Deny", fmt.Sprintf("login|%s|%s", pendingId, constant.LoginDenied)),
+	))
+
+	// 先登记再发送，避免管理员在登记前点击按钮
+	loginApprovalMutex.Lock()
+	loginApprovals[pendingId] = &loginApproval{
+		username: username,
+		ip:       ip,
+		token:    token,
+		status:   constant.LoginPending,
+		done:     make(chan struct{}),
+	}
+	loginApprovalMutex.Unlock()
+	if err = sendChattable(chatId, text, message); err != nil {
+		loginApprovalMutex.Lock()
+		delete(loginApprovals, pendingId)
+		loginApprovalMutex.Unlock()
+		return "", err
+	}
+
+	time.AfterFunc(loginApprovalTimeout, func() {
+		decideLoginApproval(pendingId, constant.LoginExpired)
+	})
+	return pendingId, nil
+}
+
+var RequestLoginApproval = requestLoginApproval
+
+// decideLoginApproval 审批结果只能设置一次
+func decideLoginApproval(pendingId string, status string) (*loginApproval, error) {
+	loginApprovalMutex.Lock()
+	approval, ok := loginApprovals[pendingId]
+	if !ok || approval.status != constant.LoginPending {
+		loginApprovalMutex.Unlock()
+		return nil, errors.New("login request not exist or already handled")
+	}
+	approval.status = status
+	close(approval.done)
+	loginApprovalMutex.Unlock()
+
+	SaveLoginHistory(approval.username, approval.ip, status)
+	if status == constant.LoginExpired {
+		// 超时后前端不再轮询，保留一段时间返回 expired
+		time.AfterFunc(loginApprovalTimeout, func() {
+			loginApprovalMutex.Lock()
+			delete(loginApprovals, pendingId)
+			loginApprovalMutex.Unlock()
+		})
+	}
+	return approval, nil
+}
+
+func telegramLoginDecide(pendingId string, status string) (string, error) {
+	if status != constant.LoginApproved && status != constant.LoginDenied {
+		return "", errors.New("unsupported command")
+	}
+	approval, err := decideLoginApproval(pendingId, status)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Login %s\nUsername: %s\nIP: %s", status, approval.username, approval.ip), nil
+}
+
+// WaitLoginApproval 长轮询等待审批结果，通过后只返回一次 token
+func WaitLoginApproval(pendingId string, wait time.Duration) (string, string, error) {
+	loginApprovalMutex.Lock()
+	approval, ok := loginApprovals[pendingId]
+	loginApprovalMutex.Unlock()
+	if !ok {
+		return "", "", errors.New("login request not exist")
+	}
+
+	select {
+	case <-approval.done:
+	case <-time.After(wait):
+	}
+
+	loginApprovalMutex.Lock()
+	defer loginApprovalMutex.Unlock()
+	switch approval.status {
+	case constant.LoginApproved:
+		delete(loginApprovals, pendingId)
+		return approval.status, approval.token, nil
+	case constant.LoginDenied:
+		delete(loginApprovals, pendingId)
+	}
+	return approval.status, "", nil
+}
+
+func SaveLoginHistory(username string, ip string, status string) {
+	loginAt := time.Now().UnixMilli()
+	if _, err := dao.SaveLoginHistory(entity.LoginHistory{
+		Username: &username,
+		Ip:       &ip,
+		Status:   &status,
+		LoginAt:  &loginAt,
+	}); err != nil {
+		logrus.Warnf("save login history err: %v", err)
+	}
+}
+
+func PageLoginHistory(loginHistoryPageDto dto.LoginHistoryPageDto) ([]entity.LoginHistory, int64, error) {
+	return dao.PageLoginHistory(loginHistoryPageDto)
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"testing"
+
+	"h-ui/dao"
+	"h-ui/model/constant"
+)
+
+func TestRequestLoginApprovalSendFail(t *testing.T) {
+	if err := dao.UpdateConfig([]string{constant.TelegramChatId}, map[string]interface{}{"value": "1"}); err != nil {
+		t.Fatal(err)
+	}
+	defer dao.UpdateConfig([]string{constant.TelegramChatId}, map[string]interface{}{"value": ""})
+
+	// bot 未初始化时发送失败，不应留下待审批的登录
+	if _, err := requestLoginApproval("sysadmin", "1.2.3.4", "token"); err == nil {
+		t.Fatal("expected send error")
+	}
+	loginApprovalMutex.Lock()
+	defer loginApprovalMutex.Unlock()
+	if len(loginApprovals) != 0 {
+		t.Fatalf("pending approvals should be removed: %d", len(loginApprovals))
+	}
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"h-ui/dao"
+	"h-ui/model/constant"
+)
+
+// TestMain 使用临时目录中的数据库
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "h-ui-service")
+	if err != nil {
+		panic(err)
+	}
+	if err = os.Mkdir(filepath.Join(dir, constant.SqliteDBDir), os.ModePerm); err != nil {
+		panic(err)
+	}
+	_ = os.Setenv("HUI_DATA", dir+string(filepath.Separator))
+	if err = dao.InitSql(""); err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	_ = dao.CloseSqliteDB()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"h-ui/dao"
+	"h-ui/model/constant"
+	"h-ui/util"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var httpDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type httpDurationHistogram struct {
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+var (
+	metricsMutex       sync.Mutex
+	authCounter        = map[[2]string]uint64{}
+	httpDuration       = map[[2]string]*httpDurationHistogram{}
+	hysteria2Restarted uint64
+	// metricsCpuPercent 后台采样的 CPU 使用率，采样需要 1 秒，不能在抓取时计算
+	metricsCpuPercent atomic.Pointer[float64]
+	metricsCpuMutex   sync.Mutex
+)
+
+// MetricsAuth 记录 hysteria2 认证结果
+func MetricsAuth(result string, reason string) {
+	metricsMutex.Lock()
+	defer metricsMutex.Unlock()
+	authCounter[[2]string{result, reason}]++
+}
+
+// MetricsHttp 记录接口耗时
+func MetricsHttp(method string, route string, seconds float64) {
+	metricsMutex.Lock()
+	defer metricsMutex.Unlock()
+	key := [2]string{method, route}
+	histogram, ok := httpDuration[key]
+	if !ok {
+		histogram = &httpDurationHistogram{buckets: make([]uint64, len(httpDurationBuckets))}
+		httpDuration[key] = histogram
+	}
+	for i, le := range httpDurationBuckets {
+		if seconds <= le {
+			histogram.buckets[i]++
+		}
+	}
+	histogram.count++
+	histogram.sum += seconds
+}
+
+func metricsHysteria2Restart() {
+	atomic.AddUint64(&hysteria2Restarted, 1)
+}
+
+// MetricsAllow 校验抓取请求的 token 和 IP 白名单
+func MetricsAllow(token string, clientIP string) bool {
+	configs, err := dao.ListConfig("key in ?", []string{
+		constant.MetricsEnable,
+		constant.MetricsToken,
+		constant.MetricsAllowIps})
+	if err != nil {
+		return false
+	}
+	var metricsEnable, metricsToken, metricsAllowIps = "0", "", ""
+	for _, item := range configs {
+		if item.Value != nil {
+			key := *item.Key
+			value := *item.Value
+			if key == constant.MetricsEnable {
+				metricsEnable = value
+			} else if key == constant.MetricsToken {
+				metricsToken = value
+			} else if key == constant.MetricsAllowIps {
+				metricsAllowIps = value
+			}
+		}
+	}
+	if metricsEnable != "1" {
+		return false
+	}
+	if metricsToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(metricsToken)) != 1 {
+		return false
+	}
+	ip := net.ParseIP(clientIP)
+	if ip == nil {
+		return false
+	}
+	if metricsAllowIps != "" {
+		return util.IPInCIDRs(ip, strings.Split(metricsAllowIps, ","))
+	}
+	// 未配置 token 和白名单时只允许本机访问
+	return metricsToken != "" || ip.IsLoopback()
+}
+
+// CronMetricsCpu 启用 metrics 时采样 CPU 使用率
+func CronMetricsCpu() {
+	if !metricsCpuMutex.TryLock() {
+		return
+	}
+	defer metricsCpuMutex.Unlock()
+
+	metricsEnable, err := dao.GetConfig("key = ?", constant.MetricsEnable)
+	if err != nil || *metricsEnable.Value != "1" {
+		metricsCpuPercent.Store(nil)
+		return
+	}
+	cpuPercent, err := util.GetCpuPercent()
+	if err != nil {
+		logrus.Warnf("metrics cpu query err: %v", err)
+		return
+	}
+	metricsCpuPercent.Store(&cpuPercent)
+}
+
+// Metrics Prometheus 文本格式
+func Metrics() string {
+	var b strings.Builder
+
+	accounts, err := dao.ListAccount(nil, nil)
+	if err == nil {
+		writeMetricsHeader(&b, "hui_account_upload_bytes_total", "counter", "Account upload traffic in bytes.")
+		for _, item := range accounts {
+			fmt.Fprintf(&b, "hui_account_upload_bytes_total{username=\"%s\"} %d\n", escapeLabel(*item.Username), *item.Upload)
+		}
+		writeMetricsHeader(&b, "hui_account_download_bytes_total", "counter", "Account download traffic in bytes.")
+		for _, item := range accounts {
+			fmt.Fprintf(&b, "hui_account_download_bytes_total{username=\"%s\"} %d\n", escapeLabel(*item.Username), *item.Download)
+		}
+	}
+
+	onlineUsers, err := Hysteria2Online()
+	if err == nil {
+		var deviceTotal int64
+		for _, value := range onlineUsers {
+			deviceTotal += value
+		}
+		writeMetricsHeader(&b, "hui_online_users", "gauge", "Number of online users.")
+		fmt.Fprintf(&b, "hui_online_users %d\n", len(onlineUsers))
+		writeMetricsHeader(&b, "hui_online_devices", "gauge", "Number of online devices.")
+		fmt.Fprintf(&b, "hui_online_devices %d\n", deviceTotal)
+	}
+
+	hysteria2Up := 0
+	if Hysteria2IsRunning() {
+		hysteria2Up = 1
+	}
+	writeMetricsHeader(&b, "hui_hysteria2_up", "gauge", "Whether hysteria2 is running.")
+	fmt.Fprintf(&b, "hui_hysteria2_up %d\n", hysteria2Up)
+	writeMetricsHeader(&b, "hui_hysteria2_restarts_total", "counter", "Number of hysteria2 restarts.")
+	fmt.Fprintf(&b, "hui_hysteria2_restarts_total %d\n", atomic.LoadUint64(&hysteria2Restarted))
+
+	metricsMutex.Lock()
+	writeMetricsHeader(&b, "hui_hysteria2_auth_total", "counter", "Hysteria2 auth requests by result and reason.")
+	authKeys := make([][2]string, 0, len(authCounter))
+	for key := range authCounter {
+		authKeys = append(authKeys, key)
+	}
+	sort.Slice(authKeys, func(i, j int) bool {
+		return authKeys[i][0]+authKeys[i][1] < authKeys[j][0]+authKeys[j][1]
+	})
+	for _, key := range authKeys {
+		fmt.Fprintf(&b, "hui_hysteria2_auth_total{result=\"%s\",reason=\"%s\"} %d\n", key[0], key[1], authCounter[key])
+	}
+
+	writeMetricsHeader(&b, "hui_http_request_duration_seconds", "histogram", "HTTP request latency by route.")
+	httpKeys := make([][2]string, 0, len(httpDuration))
+	for key := range httpDuration {
+		httpKeys = append(httpKeys, key)
+	}
+	sort.Slice(httpKeys, func(i, j int) bool {
+		return httpKeys[i][1]+httpKeys[i][0] < httpKeys[j][1]+httpKeys[j][0]
+	})
+	for _, key := range httpKeys {
+		histogram := httpDuration[key]
+		labels := fmt.Sprintf("method=\"%s\",route=\"%s\"", key[0], escapeLabel(key[1]))
+		for i, le := range httpDurationBuckets {
+			fmt.Fprintf(&b, "hui_http_request_duration_seconds_bucket{%s,le=\"%g\"} %d\n", labels, le, histogram.buckets[i])
+		}
+		fmt.Fprintf(&b, "hui_http_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, histogram.count)
+		fmt.Fprintf(&b, "hui_http_request_duration_seconds_sum{%s} %g\n", labels, histogram.sum)
+		fmt.Fprintf(&b, "hui_http_request_duration_seconds_count{%s} %d\n", labels, histogram.count)
+	}
+	metricsMutex.Unlock()
+
+	if cpuPercent := metricsCpuPercent.Load(); cpuPercent != nil {
+		writeMetricsHeader(&b, "hui_cpu_percent", "gauge", "CPU usage in percent.")
+		fmt.Fprintf(&b, "hui_cpu_percent %g\n", *cpuPercent)
+	}
+	if memPercent, err := util.GetMemPercent(); err == nil {
+		writeMetricsHeader(&b, "hui_memory_percent", "gauge", "Memory usage in percent.")
+		fmt.Fprintf(&b, "hui_memory_percent %g\n", memPercent)
+	} else {
+		logrus.Warnf("metrics mem query err: %v", err)
+	}
+	if diskPercent, err := util.GetDiskPercent(); err == nil {
+		writeMetricsHeader(&b, "hui_disk_percent", "gauge", "Disk usage in percent.")
+		fmt.Fprintf(&b, "hui_disk_percent %g\n", diskPercent)
+	} else {
+		logrus.Warnf("metrics disk query err: %v", err)
+	}
+
+	return b.String()
+}
+
+func writeMetricsHeader(b *strings.Builder, name string, metricType string, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
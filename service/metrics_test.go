@@ -0,0 +1,36 @@
+package service
+
+import (
+	"testing"
+
+	"h-ui/dao"
+	"h-ui/model/constant"
+)
+
+func TestMetricsAllow(t *testing.T) {
+	setConfig := func(key string, value string) {
+		if err := dao.UpdateConfig([]string{key}, map[string]interface{}{"value": value}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func() {
+		setConfig(constant.MetricsEnable, "0")
+		setConfig(constant.MetricsToken, "")
+	}()
+
+	setConfig(constant.MetricsEnable, "1")
+	if !MetricsAllow("", "127.0.0.1") || MetricsAllow("", "1.2.3.4") {
+		t.Fatal("only loopback should be allowed without a token")
+	}
+	setConfig(constant.MetricsToken, "secret")
+	cases := map[string]bool{"secret": true, "": false, "secre": false, "secret1": false}
+	for token, expected := range cases {
+		if MetricsAllow(token, "1.2.3.4") != expected {
+			t.Errorf("token %q: expected %v", token, expected)
+		}
+	}
+	setConfig(constant.MetricsEnable, "0")
+	if MetricsAllow("secret", "127.0.0.1") {
+		t.Fatal("disabled metrics should be forbidden")
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"h-ui/dao"
+	"h-ui/model/bo"
+	"h-ui/model/entity"
+	"strconv"
+	"strings"
+)
+
+func validNode(node entity.Node) error {
+	if node.PortHopping != nil {
+		if _, err := portRanges(*node.PortHopping); err != nil {
+			return fmt.Errorf("port hopping: %s is invalid", *node.PortHopping)
+		}
+	}
+	if node.Name != nil {
+		exist, err := dao.ListNode("name = ?", *node.Name)
+		if err != nil {
+			return err
+		}
+		for _, item := range exist {
+			if node.Id == nil || *item.Id != *node.Id {
+				return errors.New("node name already exists")
+			}
+		}
+	}
+	return nil
+}
+
+func SaveNode(node entity.Node) error {
+	if err := validNode(node); err != nil {
+		return err
+	}
+	_, err := dao.SaveNode(node)
+	return err
+}
+
+func UpdateNode(node entity.Node) error {
+	if err := validNode(node); err != nil {
+		return err
+	}
+	updates := map[string]interface{}{}
+	if node.Name != nil && *node.Name != "" {
+		updates["name"] = *node.Name
+	}
+	if node.Host != nil && *node.Host != "" {
+		updates["host"] = *node.Host
+	}
+	if node.Port != nil && *node.Port > 0 {
+		updates["port"] = *node.Port
+	}
+	if node.PortHopping != nil {
+		updates["port_hopping"] = *node.PortHopping
+	}
+	if node.Sni != nil {
+		updates["sni"] = *node.Sni
+	}
+	if node.ObfsPassword != nil {
+		updates["obfs_password"] = *node.ObfsPassword
+	}
+	if node.Sort != nil {
+		updates["sort"] = *node.Sort
+	}
+	if node.Enable != nil {
+		updates["enable"] = *node.Enable
+	}
+	return dao.UpdateNode([]int64{*node.Id}, updates)
+}
+
+func DeleteNode(ids []int64) error {
+	return dao.DeleteNode(ids)
+}
+
+func ListNode() ([]entity.Node, error) {
+	return dao.ListNode(nil, nil)
+}
+
+// subscribeNodes 本机节点在前，之后是已启用的其他节点
+func subscribeNodes(local bo.Hysteria2Node) ([]bo.Hysteria2Node, error) {
+	nodes := []bo.Hysteria2Node{local}
+	others, err := dao.ListNode("enable = 1")
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range others {
+		nodes = append(nodes, registeredHysteria2Node(item, local.Password))
+	}
+	return uniqueNodeNames(nodes), nil
+}
+
+// registeredHysteria2Node 已登记的节点，连接密码和本机相同
+func registeredHysteria2Node(node entity.Node, password string) bo.Hysteria2Node {
+	return bo.Hysteria2Node{
+		Name:         *node.Name,
+		Server:       strings.Trim(*node.Host, "[]"),
+		Port:         strconv.FormatInt(*node.Port, 10),
+		Ports:        *node.PortHopping,
+		Password:     password,
+		ObfsPassword: *node.ObfsPassword,
+		Sni:          *node.Sni,
+	}
+}
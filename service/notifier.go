@@ -0,0 +1,154 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"h-ui/dao"
+	"h-ui/model/constant"
+	"strings"
+	"sync"
+)
+
+// ErrNotifyNoChannel 事件没有可用的渠道
+var ErrNotifyNoChannel = errors.New("no notify channel available")
+
+// Notifier 通知渠道
+type Notifier interface {
+	Send(event string, title string, text string) error
+}
+
+// notifierFactories 根据配置创建渠道，未配置时返回错误
+var notifierFactories = map[string]func(configs map[string]string) (Notifier, error){
+	constant.NotifyChannelTelegram: newTelegramNotifier,
+	constant.NotifyChannelWebhook:  newWebhookNotifier,
+	constant.NotifyChannelSmtp:     newSmtpNotifier,
+}
+
+var notifyConfigKeys = []string{
+	constant.TelegramEnable,
+	constant.TelegramChatId,
+	constant.NotifyRoutes,
+	constant.NotifyWebhookUrl,
+	constant.NotifyWebhookSecret,
+	constant.NotifyWebhookRetry,
+	constant.NotifySmtpHost,
+	constant.NotifySmtpPort,
+	constant.NotifySmtpUsername,
+	constant.NotifySmtpPassword,
+	constant.NotifySmtpFrom,
+	constant.NotifySmtpTo,
+}
+
+func notifyConfigs() (map[string]string, error) {
+	configs, err := dao.ListConfig("key in ?", notifyConfigKeys)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]string{}
+	for _, item := range configs {
+		if item.Value != nil {
+			values[*item.Key] = *item.Value
+		}
+	}
+	return values, nil
+}
+
+// notifyRoutes 事件对应的渠道，未配置的事件默认发送到 Telegram
+func notifyRoutes(configs map[string]string, event string) []string {
+	routes := map[string][]string{}
+	if value := configs[constant.NotifyRoutes]; value != "" {
+		if err := json.Unmarshal([]byte(value), &routes); err != nil {
+			logrus.Errorf("parse %s err: %v", constant.NotifyRoutes, err)
+		}
+	}
+	channels, ok := routes[event]
+	if !ok {
+		return []string{constant.NotifyChannelTelegram}
+	}
+	return channels
+}
+
+// Notify 按事件路由发送通知，未配置的渠道会被跳过，任一渠道成功即返回 nil
+func Notify(event string, text string) error {
+	configs, err := notifyConfigs()
+	if err != nil {
+		return err
+	}
+	var channels []string
+	var notifiers []Notifier
+	for _, channel := range notifyRoutes(configs, event) {
+		factory, ok := notifierFactories[channel]
+		if !ok {
+			logrus.Warnf("notify channel %s not supported", channel)
+			continue
+		}
+		notifier, err := factory(configs)
+		if err != nil {
+			continue
+		}
+		channels = append(channels, channel)
+		notifiers = append(notifiers, notifier)
+	}
+	if len(notifiers) == 0 {
+		return ErrNotifyNoChannel
+	}
+	title := fmt.Sprintf("[H UI] %s", event)
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(channels))
+	for i, channel := range channels {
+		notifier := notifiers[i]
+		wg.Add(1)
+		go func(i int, channel string, notifier Notifier) {
+			defer wg.Done()
+			if err := notifier.Send(event, title, text); err != nil {
+				errs[i] = fmt.Errorf("notify channel %s: %w", channel, err)
+			}
+		}(i, channel, notifier)
+	}
+	wg.Wait()
+
+	var messages []string
+	for _, err := range errs {
+		if err != nil {
+			logrus.Warnf("notify %s err: %v", event, err)
+			messages = append(messages, err.Error())
+		}
+	}
+	if len(messages) == len(channels) {
+		return errors.New(strings.Join(messages, "; "))
+	}
+	return nil
+}
+
+// NotifyTest 向指定渠道发送测试消息
+func NotifyTest(channel string) error {
+	configs, err := notifyConfigs()
+	if err != nil {
+		return err
+	}
+	factory, ok := notifierFactories[channel]
+	if !ok {
+		return fmt.Errorf("notify channel %s not supported", channel)
+	}
+	notifier, err := factory(configs)
+	if err != nil {
+		return err
+	}
+	return notifier.Send("test", "[H UI] test", "This is a test message from H UI")
+}
+
+type telegramNotifier struct{}
+
+func newTelegramNotifier(configs map[string]string) (Notifier, error) {
+	if configs[constant.TelegramEnable] != "1" || configs[constant.TelegramChatId] == "" {
+		return nil, errors.New("telegram not enable")
+	}
+	return telegramNotifier{}, nil
+}
+
+func (t telegramNotifier) Send(event string, title string, text string) error {
+	return TelegramNotifyAdmin(text)
+}
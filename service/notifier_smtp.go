@@ -0,0 +1,126 @@
+package service
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"h-ui/model/constant"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+type smtpNotifier struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+func newSmtpNotifier(configs map[string]string) (Notifier, error) {
+	if configs[constant.NotifySmtpHost] == "" || configs[constant.NotifySmtpTo] == "" {
+		return nil, errors.New("smtp host or recipient is empty")
+	}
+	var to []string
+	for _, item := range strings.Split(configs[constant.NotifySmtpTo], ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			to = append(to, item)
+		}
+	}
+	port := configs[constant.NotifySmtpPort]
+	if port == "" {
+		port = "587"
+	}
+	from := configs[constant.NotifySmtpFrom]
+	if from == "" {
+		from = configs[constant.NotifySmtpUsername]
+	}
+	return smtpNotifier{
+		host:     configs[constant.NotifySmtpHost],
+		port:     port,
+		username: configs[constant.NotifySmtpUsername],
+		password: configs[constant.NotifySmtpPassword],
+		from:     from,
+		to:       to,
+	}, nil
+}
+
+func (s smtpNotifier) Send(event string, title string, text string) error {
+	client, err := s.dial()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if s.username != "" {
+		if err = client.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return err
+		}
+	}
+	if err = client.Mail(s.from); err != nil {
+		return err
+	}
+	for _, item := range s.to {
+		if err = client.Rcpt(item); err != nil {
+			return err
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = writer.Write(s.message(title, text)); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// dial 465 端口使用 TLS 直连，其他端口在服务端支持时使用 STARTTLS
+func (s smtpNotifier) dial() (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.host, s.port)
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if s.port == "465" {
+		conn, err := tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: s.host})
+		if err != nil {
+			return nil, err
+		}
+		return smtp.NewClient(conn, s.host)
+	}
+	conn, err := dialer.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			_ = client.Close()
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+func (s smtpNotifier) message(title string, text string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(s.to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", title))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(text, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"h-ui/model/constant"
+)
+
+func TestWebhookNotifierRetryAndSignature(t *testing.T) {
+	var calls int
+	var payload webhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("X-HUI-Signature") != webhookSignature("secret", body) {
+			t.Errorf("signature mismatch")
+		}
+		if r.Header.Get("X-HUI-Event") != constant.NotifyEventAlert {
+			t.Errorf("event header %s", r.Header.Get("X-HUI-Event"))
+		}
+		_ = json.Unmarshal(body, &payload)
+	}))
+	defer server.Close()
+
+	notifier, err := newWebhookNotifier(map[string]string{
+		constant.NotifyWebhookUrl:    server.URL,
+		constant.NotifyWebhookSecret: "secret",
+		constant.NotifyWebhookRetry:  "2",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	webhook := notifier.(webhookNotifier)
+	webhook.backoff = func(int) time.Duration { return 0 }
+	if err = webhook.Send(constant.NotifyEventAlert, "title", "cpu high"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Fatalf("calls %d", calls)
+	}
+	if payload.Text != "cpu high" || payload.Event != constant.NotifyEventAlert {
+		t.Fatalf("payload %+v", payload)
+	}
+}
+
+func TestWebhookNotifierGiveUp(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	webhook := webhookNotifier{url: server.URL, retry: 1, client: server.Client(),
+		backoff: func(int) time.Duration { return 0 }}
+	if err := webhook.Send(constant.NotifyEventLogin, "title", "text"); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 2 {
+		t.Fatalf("calls %d", calls)
+	}
+}
+
+// serveSmtp 最小的 SMTP 服务端，返回收到的邮件内容
+func serveSmtp(listener net.Listener, data chan<- string) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	write := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+	write("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			write("250 localhost")
+		case strings.HasPrefix(command, "DATA"):
+			write("354 go ahead")
+			var b strings.Builder
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				b.WriteString(line)
+			}
+			data <- b.String()
+			write("250 ok")
+		case strings.HasPrefix(command, "QUIT"):
+			write("221 bye")
+			return
+		default:
+			write("250 ok")
+		}
+	}
+}
+
+func TestSmtpNotifier(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	data := make(chan string, 1)
+	go serveSmtp(listener, data)
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	notifier, err := newSmtpNotifier(map[string]string{
+		constant.NotifySmtpHost: host,
+		constant.NotifySmtpPort: port,
+		constant.NotifySmtpFrom: "hui@example.com",
+		constant.NotifySmtpTo:   "a@example.com, b@example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = notifier.Send(constant.NotifyEventExpiry, "[H UI] expiry", "user1 expired"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case message := <-data:
+		if !strings.Contains(message, "To: a@example.com, b@example.com") || !strings.Contains(message, "user1 expired") {
+			t.Fatalf("message %q", message)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("no message received")
+	}
+}
+
+func TestNotifyRoutes(t *testing.T) {
+	configs := map[string]string{constant.NotifyRoutes: `{"alert":["webhook","smtp"],"login":[]}`}
+	if routes := notifyRoutes(configs, constant.NotifyEventAlert); len(routes) != 2 {
+		t.Fatalf("alert routes %v", routes)
+	}
+	if routes := notifyRoutes(configs, constant.NotifyEventLogin); len(routes) != 0 {
+		t.Fatalf("login routes %v", routes)
+	}
+	if routes := notifyRoutes(configs, constant.NotifyEventQuota); len(routes) != 1 || routes[0] != constant.NotifyChannelTelegram {
+		t.Fatalf("quota routes %v", routes)
+	}
+}
+
+func TestWebhookNotifierRetryCap(t *testing.T) {
+	cases := map[string]int{"": 3, "-1": 3, "0": 0, "2": 2, "100": webhookMaxRetry}
+	for value, expected := range cases {
+		notifier, err := newWebhookNotifier(map[string]string{
+			constant.NotifyWebhookUrl:   "http://127.0.0.1",
+			constant.NotifyWebhookRetry: value,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if retry := notifier.(webhookNotifier).retry; retry != expected {
+			t.Errorf("retry %q: expected %d, got %d", value, expected, retry)
+		}
+	}
+}
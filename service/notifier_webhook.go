@@ -0,0 +1,103 @@
+package service
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"h-ui/model/constant"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// webhookMaxRetry 重试次数上限，避免不可用的 webhook 长时间阻塞通知
+const webhookMaxRetry = 5
+
+type webhookNotifier struct {
+	url    string
+	secret string
+	retry  int
+	client *http.Client
+	// backoff 第 n 次重试前的等待时间
+	backoff func(n int) time.Duration
+}
+
+type webhookPayload struct {
+	Event string `json:"event"`
+	Title string `json:"title"`
+	Text  string `json:"text"`
+	Time  int64  `json:"time"`
+}
+
+func newWebhookNotifier(configs map[string]string) (Notifier, error) {
+	if configs[constant.NotifyWebhookUrl] == "" {
+		return nil, errors.New("webhook url is empty")
+	}
+	retry, err := strconv.Atoi(configs[constant.NotifyWebhookRetry])
+	if err != nil || retry < 0 {
+		retry = 3
+	}
+	retry = min(retry, webhookMaxRetry)
+	return webhookNotifier{
+		url:    configs[constant.NotifyWebhookUrl],
+		secret: configs[constant.NotifyWebhookSecret],
+		retry:  retry,
+		client: &http.Client{Timeout: 5 * time.Second},
+		backoff: func(n int) time.Duration {
+			return time.Duration(1<<n) * 500 * time.Millisecond
+		},
+	}, nil
+}
+
+// webhookSignature 请求体的 HMAC-SHA256，格式为 sha256=<hex>
+func webhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (w webhookNotifier) Send(event string, title string, text string) error {
+	body, err := json.Marshal(webhookPayload{
+		Event: event,
+		Title: title,
+		Text:  text,
+		Time:  time.Now().UnixMilli(),
+	})
+	if err != nil {
+		return err
+	}
+	for i := 0; ; i++ {
+		if err = w.post(event, body); err == nil {
+			return nil
+		}
+		if i >= w.retry {
+			return err
+		}
+		time.Sleep(w.backoff(i))
+	}
+}
+
+func (w webhookNotifier) post(event string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-HUI-Event", event)
+	if w.secret != "" {
+		req.Header.Set("X-HUI-Signature", webhookSignature(w.secret, body))
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook response status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -2,27 +2,183 @@ package service
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"h-ui/dao"
+	"h-ui/model/constant"
 	"h-ui/util"
+	"net"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
 var server *http.Server
 
-func InitServer(addr string, handler http.Handler) {
+// unixServer 监听 unix socket，供本机的反向代理使用，未配置时为空
+var unixServer *http.Server
+
+// serverRealIpHeader 传递客户端 IP 的请求头，unix socket 请求直接使用该请求头
+var serverRealIpHeader string
+
+// unixSocketKey 标记连接来自 unix socket
+type unixSocketKey struct{}
+
+func InitServer(addr string, unixSocket string, handler http.Handler) {
 	server = &http.Server{
 		Addr:    addr,
 		Handler: handler,
 	}
+	unixServer = nil
+	if unixSocket != "" {
+		unixServer = &http.Server{
+			Addr: unixSocket,
+			ConnContext: func(ctx context.Context, c net.Conn) context.Context {
+				return context.WithValue(ctx, unixSocketKey{}, true)
+			},
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// 没有客户端 IP 时拒绝，不能当作本机请求，否则会绕过 metrics 和 IP 过滤的本机规则
+				remoteAddr, ok := unixClientAddr(r)
+				if !ok {
+					http.Error(w, fmt.Sprintf("%s header is required on the unix socket", serverRealIpHeader), http.StatusBadRequest)
+					return
+				}
+				r.RemoteAddr = remoteAddr
+				handler.ServeHTTP(w, r)
+			}),
+		}
+	}
+}
+
+// FromUnixSocket 请求是否来自 unix socket，只有能访问 socket 文件的反向代理才能连接
+func FromUnixSocket(r *http.Request) bool {
+	fromUnixSocket, _ := r.Context().Value(unixSocketKey{}).(bool)
+	return fromUnixSocket
+}
+
+// unixClientAddr 使用反向代理在请求头中追加的最后一个 IP，并删除请求头，避免再按可信代理解析
+// 请求头缺失或不是 IP 时返回 false
+func unixClientAddr(r *http.Request) (string, bool) {
+	if serverRealIpHeader == "" {
+		return "", false
+	}
+	values := strings.Split(r.Header.Get(serverRealIpHeader), ",")
+	r.Header.Del(serverRealIpHeader)
+	ip := net.ParseIP(strings.TrimSpace(values[len(values)-1]))
+	if ip == nil {
+		return "", false
+	}
+	return net.JoinHostPort(ip.String(), "0"), true
+}
+
+// InitClientIP 只信任配置的代理传递的客户端 IP，日志、限流和登录记录都使用 c.ClientIP()
+func InitClientIP(engine *gin.Engine) error {
+	trustedProxies, err := getTrustedProxies()
+	if err != nil {
+		return err
+	}
+	realIpHeader, err := GetConfig(constant.HUIRealIpHeader)
+	if err != nil {
+		return err
+	}
+	engine.ForwardedByClientIP = true
+	engine.RemoteIPHeaders = nil
+	serverRealIpHeader = *realIpHeader.Value
+	if *realIpHeader.Value != "" {
+		engine.RemoteIPHeaders = []string{*realIpHeader.Value}
+	}
+	return engine.SetTrustedProxies(trustedProxies)
+}
+
+// GetServerListen 面板监听的 IP 和 unix socket 路径，IP 为空时监听所有网卡
+func GetServerListen() (string, string, error) {
+	configs, err := dao.ListConfig("key in ?", []string{constant.HUIBindAddress, constant.HUIUnixSocket})
+	if err != nil {
+		return "", "", err
+	}
+	var bindAddress, unixSocket string
+	for _, item := range configs {
+		switch *item.Key {
+		case constant.HUIBindAddress:
+			bindAddress = strings.Trim(*item.Value, "[]")
+		case constant.HUIUnixSocket:
+			unixSocket = *item.Value
+		}
+	}
+	return bindAddress, unixSocket, nil
+}
+
+// getUnixSocketMode socket 文件的权限，默认 0660，只允许同组的反向代理连接
+func getUnixSocketMode() (os.FileMode, error) {
+	config, err := GetConfig(constant.HUIUnixSocketMode)
+	if err != nil {
+		return 0, err
+	}
+	mode, err := ParseUnixSocketMode(*config.Value)
+	if err != nil {
+		return 0, err
+	}
+	return mode, nil
+}
+
+// ParseUnixSocketMode 解析八进制权限，例如 0660
+func ParseUnixSocketMode(value string) (os.FileMode, error) {
+	mode, err := strconv.ParseUint(value, 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, fmt.Errorf("unix socket mode: %s is invalid", value)
+	}
+	return os.FileMode(mode), nil
+}
+
+// listenUnixSocket 删除上次未清理的 socket 文件后监听
+func listenUnixSocket(path string, mode os.FileMode) (net.Listener, error) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.Chmod(path, mode); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	return listener, nil
 }
 
 func StartServer(crtPath string, keyPath string) error {
+	serverRestarting.Store(false)
+	if unixServer != nil {
+		mode, err := getUnixSocketMode()
+		if err != nil {
+			return err
+		}
+		listener, err := listenUnixSocket(unixServer.Addr, mode)
+		if err != nil {
+			logrus.Errorf("listen unix socket %s err: %v", unixServer.Addr, err)
+			return fmt.Errorf("listen unix socket %s err", unixServer.Addr)
+		}
+		go func(unixServer *http.Server) {
+			if err := unixServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+				logrus.Errorf("unix socket server err: %v", err)
+			}
+		}(unixServer)
+	}
 	if crtPath != "" && keyPath != "" {
-		return server.ListenAndServeTLS(crtPath, keyPath)
+		cert, err := loadServerCert(crtPath, keyPath)
+		if err != nil {
+			return err
+		}
+		serverCert.Store(cert)
+		server.TLSConfig = &tls.Config{GetCertificate: getServerCertificate}
+		return server.ListenAndServeTLS("", "")
 	}
+	serverCert.Store(nil)
 	return server.ListenAndServe()
 }
 
@@ -37,6 +193,12 @@ func StopServer() error {
 		logrus.Errorf("failed to shutdown server: %v", err)
 		return errors.New("failed to shutdown server")
 	}
+	if unixServer != nil {
+		if err := unixServer.Shutdown(ctx); err != nil {
+			logrus.Errorf("failed to shutdown unix socket server: %v", err)
+			return errors.New("failed to shutdown server")
+		}
+	}
 
 	return nil
 }
@@ -46,6 +208,13 @@ func GetServerPortAndCert() (int64, string, string, error) {
 	if err != nil {
 		return 0, "", "", err
 	}
+	if crtPath == "" {
+		if certFromHysteria2, err := GetConfig(constant.HUICertFromHysteria2); err == nil && *certFromHysteria2.Value == "1" {
+			if _, err = Hysteria2AcmePath(); err != nil {
+				logrus.Warnf("%s is enabled but the hysteria2 cert is unavailable, using http: %v", constant.HUICertFromHysteria2, err)
+			}
+		}
+	}
 
 	if !util.IsPortAvailable(uint(port), "tcp") {
 		errMsg := fmt.Sprintf("port %d is taken", port)
@@ -67,3 +236,59 @@ func GetServerPortAndCert() (int64, string, string, error) {
 
 	return port, crtPath, keyPath, nil
 }
+
+// getTrustedProxies 配置的可信代理
+func getTrustedProxies() ([]string, error) {
+	trustedProxies, err := GetConfig(constant.HUITrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	return splitFilterList(*trustedProxies.Value), nil
+}
+
+// TrustedProxy 请求来自可信代理时才使用 X-Forwarded-* 请求头
+func TrustedProxy(remoteIP string) bool {
+	ip := net.ParseIP(remoteIP)
+	if ip == nil {
+		return false
+	}
+	trustedProxies, err := getTrustedProxies()
+	if err != nil {
+		return false
+	}
+	return util.IPInCIDRs(ip, trustedProxies)
+}
+
+// ValidTrustedProxies 校验逗号分隔的 IP 或 CIDR
+func ValidTrustedProxies(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(item); err == nil {
+			continue
+		}
+		if net.ParseIP(item) == nil {
+			return fmt.Errorf("trusted proxy: %s is invalid", item)
+		}
+	}
+	return nil
+}
+
+// ValidBindAddress 校验面板监听的 IP，为空时监听所有网卡，不是本机的 IP 会导致面板无法启动
+func ValidBindAddress(value string) error {
+	if value == "" {
+		return nil
+	}
+	ip := net.ParseIP(strings.Trim(value, "[]"))
+	if ip == nil {
+		return fmt.Errorf("bind address: %s is invalid", value)
+	}
+	listener, err := net.Listen("tcp", net.JoinHostPort(ip.String(), "0"))
+	if err != nil {
+		return fmt.Errorf("bind address: %s is not available", value)
+	}
+	_ = listener.Close()
+	return nil
+}
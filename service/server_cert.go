@@ -0,0 +1,119 @@
+package service
+
+import (
+	"crypto/tls"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// loadedServerCert 面板当前使用的证书，记录文件修改时间用于判断是否需要重新加载
+type loadedServerCert struct {
+	crtPath    string
+	keyPath    string
+	crtModTime time.Time
+	keyModTime time.Time
+	cert       *tls.Certificate
+}
+
+// serverCert 为空表示面板没有使用 TLS
+var serverCert atomic.Pointer[loadedServerCert]
+var serverCertMutex sync.Mutex
+
+// serverRestarting 已因证书变化触发重启，面板重新启动前不再检查
+var serverRestarting atomic.Bool
+
+func loadServerCert(crtPath string, keyPath string) (*loadedServerCert, error) {
+	crtInfo, err := os.Stat(crtPath)
+	if err != nil {
+		return nil, err
+	}
+	keyInfo, err := os.Stat(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.LoadX509KeyPair(crtPath, keyPath)
+	if err != nil {
+		return nil, err
+	}
+	return &loadedServerCert{
+		crtPath:    crtPath,
+		keyPath:    keyPath,
+		crtModTime: crtInfo.ModTime(),
+		keyModTime: keyInfo.ModTime(),
+		cert:       &cert,
+	}, nil
+}
+
+func getServerCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	current := serverCert.Load()
+	if current == nil {
+		return nil, errors.New("server cert is not loaded")
+	}
+	return current.cert, nil
+}
+
+// serverCertChanged 证书路径或文件修改时间是否变化
+func serverCertChanged(current *loadedServerCert, crtPath string, keyPath string) bool {
+	if current.crtPath != crtPath || current.keyPath != keyPath {
+		return true
+	}
+	crtInfo, err := os.Stat(crtPath)
+	if err != nil {
+		return false
+	}
+	keyInfo, err := os.Stat(keyPath)
+	if err != nil {
+		return false
+	}
+	return !crtInfo.ModTime().Equal(current.crtModTime) || !keyInfo.ModTime().Equal(current.keyModTime)
+}
+
+// ReloadServerCert 证书变化时重新加载，加载失败时继续使用原证书。
+// 启用或关闭 TLS 无法热加载，返回 true 表示需要重启面板
+func ReloadServerCert() (bool, error) {
+	serverCertMutex.Lock()
+	defer serverCertMutex.Unlock()
+
+	if server == nil {
+		return false, nil
+	}
+	_, crtPath, keyPath, err := GetPortAndCert()
+	if err != nil {
+		return false, err
+	}
+	current := serverCert.Load()
+	if (current == nil) != (crtPath == "" || keyPath == "") {
+		return true, nil
+	}
+	if current == nil || !serverCertChanged(current, crtPath, keyPath) {
+		return false, nil
+	}
+	cert, err := loadServerCert(crtPath, keyPath)
+	if err != nil {
+		return false, err
+	}
+	serverCert.Store(cert)
+	logrus.Infof("server cert reloaded: %s", crtPath)
+	return false, nil
+}
+
+// CronServerCert 检查面板证书文件，续期后自动生效
+func CronServerCert() {
+	if serverRestarting.Load() {
+		return
+	}
+	needRestart, err := ReloadServerCert()
+	if err != nil {
+		logrus.Warnf("reload server cert err: %v", err)
+		return
+	}
+	if needRestart {
+		logrus.Infof("server cert changed, restarting server")
+		serverRestarting.Store(true)
+		_ = StopServer()
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"h-ui/dao"
+	"h-ui/model/constant"
+)
+
+// writeServerCert 写入自签名证书，返回证书序列号
+func writeServerCert(t *testing.T, crtPath string, keyPath string) string {
+	t.Helper()
+	cert, key, err := createCert(&x509.Certificate{
+		Subject:   pkix.Name{CommonName: "example.com"},
+		NotBefore: time.Now().Add(-time.Hour),
+		NotAfter:  time.Now().Add(time.Hour),
+	}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(crtPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return cert.SerialNumber.String()
+}
+
+func setServerCertConfig(t *testing.T, crtPath string, keyPath string) {
+	t.Helper()
+	if err := dao.UpdateConfig([]string{constant.HUICrtPath}, map[string]interface{}{"value": crtPath}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dao.UpdateConfig([]string{constant.HUIKeyPath}, map[string]interface{}{"value": keyPath}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func loadedServerCertSerial(t *testing.T) string {
+	t.Helper()
+	current := serverCert.Load()
+	if current == nil {
+		t.Fatal("server cert is not loaded")
+	}
+	cert, err := x509.ParseCertificate(current.cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert.SerialNumber.String()
+}
+
+func TestReloadServerCert(t *testing.T) {
+	originServer := server
+	server = &http.Server{}
+	defer func() {
+		server = originServer
+		serverCert.Store(nil)
+		setServerCertConfig(t, "", "")
+	}()
+
+	dir := t.TempDir()
+	crtPath := filepath.Join(dir, "a.crt")
+	keyPath := filepath.Join(dir, "a.key")
+	serial := writeServerCert(t, crtPath, keyPath)
+	cert, err := loadServerCert(crtPath, keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverCert.Store(cert)
+	setServerCertConfig(t, crtPath, keyPath)
+
+	// 文件没有变化时不重新加载
+	if needRestart, err := ReloadServerCert(); needRestart || err != nil {
+		t.Fatalf("unchanged: %v %v", needRestart, err)
+	}
+	if loadedServerCertSerial(t) != serial {
+		t.Fatal("unchanged cert should not be reloaded")
+	}
+
+	// 续期后文件修改时间变化
+	serial = writeServerCert(t, crtPath, keyPath)
+	later := time.Now().Add(time.Minute)
+	_ = os.Chtimes(crtPath, later, later)
+	_ = os.Chtimes(keyPath, later, later)
+	if needRestart, err := ReloadServerCert(); needRestart || err != nil {
+		t.Fatalf("mtime changed: %v %v", needRestart, err)
+	}
+	if loadedServerCertSerial(t) != serial {
+		t.Fatal("renewed cert should be reloaded")
+	}
+
+	// 证书路径变化
+	otherCrtPath := filepath.Join(dir, "b.crt")
+	otherKeyPath := filepath.Join(dir, "b.key")
+	serial = writeServerCert(t, otherCrtPath, otherKeyPath)
+	setServerCertConfig(t, otherCrtPath, otherKeyPath)
+	if needRestart, err := ReloadServerCert(); needRestart || err != nil {
+		t.Fatalf("path changed: %v %v", needRestart, err)
+	}
+	if loadedServerCertSerial(t) != serial {
+		t.Fatal("cert at the new path should be loaded")
+	}
+
+	// 只写了一半的私钥和不匹配的私钥都继续使用原证书
+	keyPem, _ := os.ReadFile(otherKeyPath)
+	_ = os.WriteFile(otherKeyPath, keyPem[:len(keyPem)/2], 0600)
+	later = later.Add(time.Minute)
+	_ = os.Chtimes(otherKeyPath, later, later)
+	if _, err := ReloadServerCert(); err == nil {
+		t.Fatal("half written key should fail to load")
+	}
+	if loadedServerCertSerial(t) != serial {
+		t.Fatal("half written key should keep the old cert")
+	}
+	writeServerCert(t, filepath.Join(dir, "c.crt"), otherKeyPath)
+	later = later.Add(time.Minute)
+	_ = os.Chtimes(otherKeyPath, later, later)
+	if _, err := ReloadServerCert(); err == nil {
+		t.Fatal("mismatched key should fail to load")
+	}
+	if loadedServerCertSerial(t) != serial {
+		t.Fatal("mismatched key should keep the old cert")
+	}
+
+	// 关闭和启用 TLS 需要重启
+	setServerCertConfig(t, "", "")
+	if needRestart, err := ReloadServerCert(); !needRestart || err != nil {
+		t.Fatalf("disable tls: %v %v", needRestart, err)
+	}
+	serverCert.Store(nil)
+	setServerCertConfig(t, crtPath, keyPath)
+	if needRestart, err := ReloadServerCert(); !needRestart || err != nil {
+		t.Fatalf("enable tls: %v %v", needRestart, err)
+	}
+}
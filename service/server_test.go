@@ -0,0 +1,44 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUnixServerClientAddr(t *testing.T) {
+	originServer, originUnixServer, originHeader := server, unixServer, serverRealIpHeader
+	defer func() {
+		server, unixServer, serverRealIpHeader = originServer, originUnixServer, originHeader
+	}()
+
+	var remoteAddr string
+	InitServer(":0", "/tmp/h-ui-test.sock", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remoteAddr = r.RemoteAddr
+	}))
+	serve := func(header string, value string) int {
+		remoteAddr = ""
+		req := httptest.NewRequest("GET", "/metrics", nil)
+		if value != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		unixServer.Handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	serverRealIpHeader = "X-Forwarded-For"
+	if code := serve("X-Forwarded-For", "1.1.1.1, 2.2.2.2"); code != http.StatusOK || remoteAddr != "2.2.2.2:0" {
+		t.Fatalf("unexpected %d %s", code, remoteAddr)
+	}
+	// 缺少请求头或不是 IP 时拒绝，不能当作本机请求
+	for _, value := range []string{"", "unknown"} {
+		if code := serve("X-Forwarded-For", value); code != http.StatusBadRequest || remoteAddr != "" {
+			t.Fatalf("%q: unexpected %d %s", value, code, remoteAddr)
+		}
+	}
+	serverRealIpHeader = ""
+	if code := serve("X-Forwarded-For", "1.1.1.1"); code != http.StatusBadRequest {
+		t.Fatalf("unexpected %d", code)
+	}
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"h-ui/dao"
+	"h-ui/model/bo"
+	"h-ui/model/constant"
+	"h-ui/model/entity"
+	"h-ui/util"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// subscribeFormatAlias 订阅格式的常用别名
+var subscribeFormatAlias = map[string]string{
+	"singbox":  constant.SingBox,
+	"sfa":      constant.SingBox,
+	"sfi":      constant.SingBox,
+	"mihomo":   constant.Clash,
+	"meta":     constant.Clash,
+	"quanx":    constant.QuantumultX,
+	"qx":       constant.QuantumultX,
+	"base64":   constant.V2rayN,
+	"hysteria": constant.Hysteria2,
+	"hy2":      constant.Hysteria2,
+}
+
+func subscribeFormat(format string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if alias, ok := subscribeFormatAlias[format]; ok {
+		format = alias
+	}
+	if !util.ArrContain(constant.SubscribeFormats, format) {
+		return "", fmt.Errorf("subscription format %s not supported", format)
+	}
+	return format, nil
+}
+
+func parseSubscribeUaRules(value string) ([]bo.SubscribeUaRule, []*regexp.Regexp, error) {
+	var rules []bo.SubscribeUaRule
+	if strings.TrimSpace(value) == "" {
+		return nil, nil, nil
+	}
+	if err := json.Unmarshal([]byte(value), &rules); err != nil {
+		return nil, nil, errors.New("subscription user-agent rules must be a json array")
+	}
+	regexps := make([]*regexp.Regexp, len(rules))
+	for i, rule := range rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, nil, fmt.Errorf("subscription user-agent rule %s is invalid", rule.Pattern)
+		}
+		if rules[i].Format, err = subscribeFormat(rule.Format); err != nil {
+			return nil, nil, err
+		}
+		regexps[i] = re
+	}
+	return rules, regexps, nil
+}
+
+// ValidSubscribeUaRules 校验 User-Agent 规则，规则按顺序匹配
+func ValidSubscribeUaRules(value string) error {
+	_, _, err := parseSubscribeUaRules(value)
+	return err
+}
+
+// ValidSubscribeFormats 校验逗号分隔的订阅格式
+func ValidSubscribeFormats(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		if _, err := subscribeFormat(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SubscribeClientType 优先使用请求指定的格式，否则按 User-Agent 规则匹配，都不匹配时使用 Clash
+func SubscribeClientType(format string, userAgent string) (string, error) {
+	configs, err := dao.ListConfig("key in ?", []string{constant.SubscribeUaRules, constant.SubscribeFormatDisabled})
+	if err != nil {
+		return "", err
+	}
+	var uaRules, disabled string
+	for _, item := range configs {
+		switch *item.Key {
+		case constant.SubscribeUaRules:
+			uaRules = *item.Value
+		case constant.SubscribeFormatDisabled:
+			disabled = *item.Value
+		}
+	}
+
+	clientType := constant.Clash
+	if format != "" {
+		if clientType, err = subscribeFormat(format); err != nil {
+			return "", err
+		}
+	} else {
+		rules, regexps, err := parseSubscribeUaRules(uaRules)
+		if err != nil {
+			return "", err
+		}
+		for i, rule := range rules {
+			if regexps[i].MatchString(userAgent) {
+				clientType = rule.Format
+				break
+			}
+		}
+	}
+
+	for _, item := range strings.Split(disabled, ",") {
+		if item, err := subscribeFormat(item); err == nil && item == clientType {
+			return "", fmt.Errorf("subscription format %s is disabled", clientType)
+		}
+	}
+	return clientType, nil
+}
+
+// hysteria2Node 根据 hysteria2 配置生成本机节点
+func hysteria2Node(hysteria2Config bo.Hysteria2ServerConfig, account entity.Account, name string, portHopping string, host string) bo.Hysteria2Node {
+	node := bo.Hysteria2Node{
+		Name:     name,
+		Server:   util.SplitHostname(host),
+		Port:     util.SplitPort(*hysteria2Config.Listen),
+		Ports:    portHopping,
+		Password: *account.ConPass,
+	}
+	if hysteria2Config.Bandwidth != nil {
+		if hysteria2Config.Bandwidth.Up != nil {
+			node.Up = *hysteria2Config.Bandwidth.Up
+		}
+		if hysteria2Config.Bandwidth.Down != nil {
+			node.Down = *hysteria2Config.Bandwidth.Down
+		}
+	}
+	if hysteria2Config.Obfs != nil &&
+		hysteria2Config.Obfs.Type != nil &&
+		*hysteria2Config.Obfs.Type == "salamander" &&
+		hysteria2Config.Obfs.Salamander != nil &&
+		hysteria2Config.Obfs.Salamander.Password != nil {
+		node.ObfsPassword = *hysteria2Config.Obfs.Salamander.Password
+	}
+	if hysteria2Config.ACME != nil && len(hysteria2Config.ACME.Domains) > 0 {
+		node.Sni = hysteria2Config.ACME.Domains[0]
+	}
+	return node
+}
+
+// subscribeUserInfo subscription-userinfo 响应头
+func subscribeUserInfo(account entity.Account) string {
+	return fmt.Sprintf("upload=%d; download=%d; total=%d; expire=%d",
+		*account.Upload,
+		*account.Download,
+		*account.Quota,
+		*account.ExpireTime/1000)
+}
+
+// lineProxyName Surge、Loon 和 Quantumult X 按逗号和等号分隔参数，节点名中的这些字符替换为空格
+func lineProxyName(name string) string {
+	name = strings.NewReplacer(",", " ", "=", " ", "\r", " ", "\n", " ").Replace(name)
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// bandwidthMbps 将 hysteria2 的带宽字符串转换为 Mbps，例如 100 mbps、1g、50000000
+func bandwidthMbps(bandwidth string) (int64, error) {
+	bandwidth = strings.ToLower(strings.TrimSpace(bandwidth))
+	if bandwidth == "" {
+		return 0, nil
+	}
+	i := strings.IndexFunc(bandwidth, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	number, unit := bandwidth, "b"
+	if i >= 0 {
+		number, unit = bandwidth[:i], strings.TrimSpace(bandwidth[i:])
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid bandwidth %s", bandwidth)
+	}
+	units := map[string]float64{
+		"b": 1e-6, "bps": 1e-6,
+		"k": 1e-3, "kb": 1e-3, "kbps": 1e-3,
+		"m": 1, "mb": 1, "mbps": 1,
+		"g": 1e3, "gb": 1e3, "gbps": 1e3,
+		"t": 1e6, "tb": 1e6, "tbps": 1e6,
+	}
+	multiple, ok := units[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid bandwidth %s", bandwidth)
+	}
+	mbps := int64(value * multiple)
+	if mbps == 0 && value > 0 {
+		mbps = 1
+	}
+	return mbps, nil
+}
+
+// portRanges 将端口跳跃配置拆分为起止端口
+func portRanges(ports string) ([][2]int64, error) {
+	var ranges [][2]int64
+	for _, item := range strings.Split(ports, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		start, end, found := strings.Cut(item, "-")
+		if !found {
+			end = start
+		}
+		startPort, err := strconv.ParseInt(strings.TrimSpace(start), 10, 64)
+		if err != nil {
+			return nil, errors.New("invalid port hopping")
+		}
+		endPort, err := strconv.ParseInt(strings.TrimSpace(end), 10, 64)
+		if err != nil || endPort < startPort {
+			return nil, errors.New("invalid port hopping")
+		}
+		ranges = append(ranges, [2]int64{startPort, endPort})
+	}
+	return ranges, nil
+}
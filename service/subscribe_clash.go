@@ -0,0 +1,182 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"h-ui/model/bo"
+)
+
+// clashSubscribe Clash 配置，Shadowrocket 的 obfs 字段直接填写混淆密码
+func clashSubscribe(nodes []bo.Hysteria2Node, shadowrocket bool) (string, error) {
+	proxyGroup := bo.ProxyGroup{
+		Name: "PROXY",
+		Type: "select",
+	}
+	var proxies []interface{}
+	for _, node := range nodes {
+		hysteria2 := bo.Hysteria2{
+			Name:           node.Name,
+			Type:           "hysteria2",
+			Server:         node.Server,
+			Port:           node.Port,
+			Ports:          node.Ports,
+			Password:       node.Password,
+			Up:             node.Up,
+			Down:           node.Down,
+			Sni:            node.Sni,
+			SkipCertVerify: node.Insecure,
+			Fingerprint:    node.PinSHA256,
+		}
+		if node.ObfsPassword != "" {
+			if shadowrocket {
+				hysteria2.Obfs = node.ObfsPassword
+			} else {
+				hysteria2.Obfs = "salamander"
+				hysteria2.ObfsPassword = node.ObfsPassword
+			}
+		}
+		proxies = append(proxies, hysteria2)
+		proxyGroup.Proxies = append(proxyGroup.Proxies, node.Name)
+	}
+	clashConfigYaml, err := yaml.Marshal(&bo.ClashConfig{
+		ProxyGroups: []bo.ProxyGroup{proxyGroup},
+		Proxies:     proxies,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(clashConfigYaml), nil
+}
+
+// clashProxiesPlaceholder 模板 proxy-groups 的 proxies 中的占位符，替换为生成的全部节点
+const clashProxiesPlaceholder = "<proxies>"
+
+func yamlMappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// mergeYamlMapping 深度合并，两边都是 mapping 时递归，否则使用 src 的值
+func mergeYamlMapping(dst *yaml.Node, src *yaml.Node) {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		existing := yamlMappingValue(dst, key.Value)
+		switch {
+		case existing == nil:
+			dst.Content = append(dst.Content, key, value)
+		case existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			mergeYamlMapping(existing, value)
+		default:
+			*existing = *value
+		}
+	}
+}
+
+func expandClashProxies(group *yaml.Node, proxyNames []string) {
+	proxies := yamlMappingValue(group, "proxies")
+	if proxies == nil || proxies.Kind != yaml.SequenceNode {
+		return
+	}
+	var content []*yaml.Node
+	for _, item := range proxies.Content {
+		if item.Kind == yaml.ScalarNode && item.Value == clashProxiesPlaceholder {
+			for _, name := range proxyNames {
+				content = append(content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name})
+			}
+			continue
+		}
+		content = append(content, item)
+	}
+	proxies.Content = content
+}
+
+// renderClashTemplate 将模板合并到生成的配置：proxies 追加，proxy-groups 按名称替换或追加，其他键深度合并
+func renderClashTemplate(configYaml string, template string, proxyNames []string) (string, error) {
+	var templateDoc yaml.Node
+	if err := yaml.Unmarshal([]byte(template), &templateDoc); err != nil {
+		return "", fmt.Errorf("clash template is invalid: %v", err)
+	}
+	if len(templateDoc.Content) == 0 {
+		return configYaml, nil
+	}
+	templateRoot := templateDoc.Content[0]
+	if templateRoot.Kind != yaml.MappingNode {
+		return "", errors.New("clash template must be a mapping")
+	}
+
+	var configDoc yaml.Node
+	if err := yaml.Unmarshal([]byte(configYaml), &configDoc); err != nil {
+		return "", err
+	}
+	root := configDoc.Content[0]
+	proxies := yamlMappingValue(root, "proxies")
+	proxyGroups := yamlMappingValue(root, "proxy-groups")
+
+	for i := 0; i+1 < len(templateRoot.Content); i += 2 {
+		key, value := templateRoot.Content[i], templateRoot.Content[i+1]
+		switch key.Value {
+		case "proxies":
+			if value.Kind != yaml.SequenceNode {
+				return "", errors.New("clash template proxies must be a list")
+			}
+			proxies.Content = append(proxies.Content, value.Content...)
+		case "proxy-groups":
+			if value.Kind != yaml.SequenceNode {
+				return "", errors.New("clash template proxy-groups must be a list")
+			}
+			for _, group := range value.Content {
+				if group.Kind != yaml.MappingNode || yamlMappingValue(group, "name") == nil {
+					return "", errors.New("clash template proxy-group must have a name")
+				}
+				expandClashProxies(group, proxyNames)
+				replaced := false
+				for j, item := range proxyGroups.Content {
+					if yamlMappingValue(item, "name").Value == yamlMappingValue(group, "name").Value {
+						proxyGroups.Content[j] = group
+						replaced = true
+						break
+					}
+				}
+				if !replaced {
+					proxyGroups.Content = append(proxyGroups.Content, group)
+				}
+			}
+		default:
+			mergeYamlMapping(root, &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{key, value}})
+		}
+	}
+
+	names := map[string]bool{}
+	for _, item := range append(append([]*yaml.Node{}, proxies.Content...), proxyGroups.Content...) {
+		name := yamlMappingValue(item, "name")
+		if name == nil {
+			continue
+		}
+		if names[name.Value] {
+			return "", fmt.Errorf("clash template: duplicate proxy or group name %s", name.Value)
+		}
+		names[name.Value] = true
+	}
+
+	configBytes, err := yaml.Marshal(&configDoc)
+	if err != nil {
+		return "", err
+	}
+	return string(configBytes), nil
+}
+
+// ValidClashTemplate 保存模板前使用示例节点渲染一次
+func ValidClashTemplate(template string) error {
+	nodes := []bo.Hysteria2Node{{Name: "hysteria2", Server: "example.com", Port: "443", Password: "password"}}
+	configYaml, err := clashSubscribe(nodes, false)
+	if err != nil {
+		return err
+	}
+	_, err = renderClashTemplate(configYaml, template, []string{"hysteria2"})
+	return err
+}
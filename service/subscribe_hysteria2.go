@@ -0,0 +1,68 @@
+package service
+
+import (
+	"gopkg.in/yaml.v3"
+	"h-ui/dao"
+	"h-ui/model/bo"
+	"h-ui/model/constant"
+	"net"
+)
+
+// hysteria2ClientSubscribe 官方 hysteria 客户端配置，只能包含一个服务器，socks5 和 http 为空时不监听
+func hysteria2ClientSubscribe(node bo.Hysteria2Node, socks5 string, http string) (string, error) {
+	port := node.Port
+	if node.Ports != "" {
+		// 端口跳跃时使用多端口地址，例如 example.com:443,20000-30000
+		port = port + "," + node.Ports
+	}
+	config := bo.Hysteria2ClientConfig{
+		Server: net.JoinHostPort(node.Server, port),
+		Auth:   node.Password,
+		TLS: bo.Hysteria2ClientTLS{
+			Sni:       node.Sni,
+			Insecure:  node.Insecure,
+			PinSHA256: node.PinSHA256,
+		},
+	}
+	if node.ObfsPassword != "" {
+		config.Obfs = &bo.Hysteria2ClientObfs{
+			Type:       "salamander",
+			Salamander: bo.Hysteria2ClientObfsSalamander{Password: node.ObfsPassword},
+		}
+	}
+	if node.Up != "" || node.Down != "" {
+		config.Bandwidth = &bo.Hysteria2ClientBandwidth{
+			Up:   node.Up,
+			Down: node.Down,
+		}
+	}
+	if socks5 != "" {
+		config.Socks5 = &bo.Hysteria2ClientListen{Listen: socks5}
+	}
+	if http != "" {
+		config.HTTP = &bo.Hysteria2ClientListen{Listen: http}
+	}
+	configYaml, err := yaml.Marshal(&config)
+	if err != nil {
+		return "", err
+	}
+	return string(configYaml), nil
+}
+
+// getHysteria2ClientListen 官方客户端默认的本地监听地址
+func getHysteria2ClientListen() (string, string, error) {
+	configs, err := dao.ListConfig("key in ?", []string{constant.Hysteria2ClientSocks5, constant.Hysteria2ClientHttp})
+	if err != nil {
+		return "", "", err
+	}
+	var socks5, http string
+	for _, item := range configs {
+		switch *item.Key {
+		case constant.Hysteria2ClientSocks5:
+			socks5 = *item.Value
+		case constant.Hysteria2ClientHttp:
+			http = *item.Value
+		}
+	}
+	return socks5, http, nil
+}
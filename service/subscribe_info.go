@@ -0,0 +1,142 @@
+package service
+
+import (
+	"h-ui/dao"
+	"h-ui/model/bo"
+	"h-ui/model/constant"
+	"h-ui/model/entity"
+	"h-ui/util"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	subscribeStatusActive    = "active"
+	subscribeStatusDisabled  = "disabled"
+	subscribeStatusOverQuota = "over quota"
+	subscribeStatusExpired   = "expired"
+)
+
+type subscribeInfo struct {
+	enable       bool
+	template     string
+	announcement string
+	notice       string
+}
+
+func getSubscribeInfo() (subscribeInfo, error) {
+	configs, err := dao.ListConfig("key in ?", []string{
+		constant.SubscribeInfoEnable,
+		constant.SubscribeInfoTemplate,
+		constant.SubscribeAnnouncement,
+		constant.SubscribeNoticeTemplate})
+	if err != nil {
+		return subscribeInfo{}, err
+	}
+	var info subscribeInfo
+	for _, item := range configs {
+		switch *item.Key {
+		case constant.SubscribeInfoEnable:
+			info.enable = *item.Value == "1"
+		case constant.SubscribeInfoTemplate:
+			info.template = *item.Value
+		case constant.SubscribeAnnouncement:
+			info.announcement = *item.Value
+		case constant.SubscribeNoticeTemplate:
+			info.notice = *item.Value
+		}
+	}
+	return info, nil
+}
+
+// subscribeStatus 与 Hysteria2Auth 的拒绝条件一致
+func subscribeStatus(account entity.Account, now time.Time) string {
+	switch {
+	case *account.Deleted != 0:
+		return subscribeStatusDisabled
+	case *account.Quota >= 0 && *account.Quota <= *account.Download+*account.Upload:
+		return subscribeStatusOverQuota
+	case now.UnixMilli() >= *account.ExpireTime:
+		return subscribeStatusExpired
+	}
+	return subscribeStatusActive
+}
+
+func subscribeInfoReplacer(account entity.Account, status string, now time.Time) *strings.Replacer {
+	used := *account.Download + *account.Upload
+	total, remaining := "Unlimited", "Unlimited"
+	if *account.Quota >= 0 {
+		total = util.FormatBytes(*account.Quota)
+		remaining = util.FormatBytes(max(*account.Quota-used, 0))
+	}
+	expire := time.UnixMilli(*account.ExpireTime)
+	expireStr := expire.Format("2006-01-02")
+	if expire.Year() >= 9999 {
+		expireStr = "Never"
+	}
+	days := strconv.FormatInt(max(int64(expire.Sub(now).Hours()/24), 0), 10)
+	return strings.NewReplacer(
+		"{username}", *account.Username,
+		"{remaining}", remaining,
+		"{used}", util.FormatBytes(used),
+		"{total}", total,
+		"{expire}", expireStr,
+		"{days}", days,
+		"{status}", status,
+	)
+}
+
+// subscribeInfoNodes 在订阅前面加入信息节点，不可用的账户只返回提示节点
+// 信息节点复制第一个节点的连接参数，选中后仍然可以使用
+func subscribeInfoNodes(account entity.Account, nodes []bo.Hysteria2Node, info subscribeInfo, now time.Time) []bo.Hysteria2Node {
+	if len(nodes) == 0 {
+		return nodes
+	}
+	status := subscribeStatus(account, now)
+	replacer := subscribeInfoReplacer(account, status, now)
+
+	var names []string
+	if status != subscribeStatusActive {
+		names = append(names, info.notice)
+	} else if info.enable {
+		names = append(names, strings.Split(info.template, "\n")...)
+	}
+	if info.enable || status != subscribeStatusActive {
+		names = append(names, info.announcement)
+	}
+
+	var infoNodes []bo.Hysteria2Node
+	for _, name := range names {
+		name = strings.TrimSpace(replacer.Replace(name))
+		if name == "" {
+			continue
+		}
+		node := nodes[0]
+		node.Name = name
+		infoNodes = append(infoNodes, node)
+	}
+	if status != subscribeStatusActive {
+		if len(infoNodes) == 0 {
+			node := nodes[0]
+			node.Name = replacer.Replace("{username} is {status}")
+			infoNodes = append(infoNodes, node)
+		}
+		return uniqueNodeNames(infoNodes)
+	}
+	return uniqueNodeNames(append(infoNodes, nodes...))
+}
+
+// uniqueNodeNames 重名时追加序号，客户端要求节点名称唯一
+func uniqueNodeNames(nodes []bo.Hysteria2Node) []bo.Hysteria2Node {
+	names := map[string]bool{}
+	for i := range nodes {
+		name := nodes[i].Name
+		for j := 2; names[name]; j++ {
+			name = nodes[i].Name + " " + strconv.Itoa(j)
+		}
+		names[name] = true
+		nodes[i].Name = name
+	}
+	return nodes
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"h-ui/dao"
+	"h-ui/model/bo"
+	"h-ui/model/constant"
+	"h-ui/model/dto"
+	"h-ui/model/entity"
+	"h-ui/util"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// authLogInterval 同一账户同一 IP 的认证记录间隔，hysteria2 每次连接都会认证
+const authLogInterval = 10 * time.Minute
+
+var authLogAt = map[string]int64{}
+var authLogMutex sync.Mutex
+var shareDetectMutex sync.Mutex
+
+// shareFlagAt 已处理过的账户和处理时间，同一窗口内只处理一次
+var shareFlagAt = map[int64]int64{}
+
+// SaveSubscribeLog 记录一次订阅拉取
+func SaveSubscribeLog(conPass string, ip string, userAgent string, format string) {
+	account, err := dao.GetAccount("con_pass = ?", conPass)
+	if err != nil {
+		return
+	}
+	saveSubscribeLog(*account.Id, constant.SubscribeLogSourceSubscribe, ip, userAgent, format)
+}
+
+// SaveAuthLog 记录 hysteria2 认证的来源 IP，addr 为 hysteria2 传入的客户端地址
+func SaveAuthLog(accountId int64, addr string) {
+	ip := util.SplitHostname(addr)
+	if ip == "" {
+		return
+	}
+	now := time.Now().UnixMilli()
+	key := fmt.Sprintf("%d/%s", accountId, ip)
+	authLogMutex.Lock()
+	if now-authLogAt[key] < authLogInterval.Milliseconds() {
+		authLogMutex.Unlock()
+		return
+	}
+	authLogAt[key] = now
+	authLogMutex.Unlock()
+	saveSubscribeLog(accountId, constant.SubscribeLogSourceAuth, ip, "", "")
+}
+
+func saveSubscribeLog(accountId int64, source string, ip string, userAgent string, format string) {
+	if len(userAgent) > 512 {
+		userAgent = userAgent[:512]
+	}
+	now := time.Now().UnixMilli()
+	_, _ = dao.SaveSubscribeLog(entity.SubscribeLog{
+		AccountId: &accountId,
+		Source:    &source,
+		Ip:        &ip,
+		UserAgent: &userAgent,
+		Format:    &format,
+		AccessAt:  &now,
+	})
+}
+
+func PageSubscribeLog(subscribeLogPageDto dto.SubscribeLogPageDto) ([]entity.SubscribeLog, int64, error) {
+	return dao.PageSubscribeLog(subscribeLogPageDto)
+}
+
+// StatSubscribeLog 账户最近 days 天的不同 IP 和客户端
+func StatSubscribeLog(accountId int64, days int64) ([]bo.SubscribeLogStat, []bo.SubscribeLogStat, error) {
+	startAt := time.Now().Add(-time.Duration(days) * 24 * time.Hour).UnixMilli()
+	ips, err := dao.StatSubscribeLog(accountId, "ip", startAt)
+	if err != nil {
+		return nil, nil, err
+	}
+	userAgents, err := dao.StatSubscribeLog(accountId, "user_agent", startAt)
+	if err != nil {
+		return nil, nil, err
+	}
+	// 认证记录没有 User-Agent
+	var clients []bo.SubscribeLogStat
+	for _, item := range userAgents {
+		if item.Value != "" {
+			clients = append(clients, item)
+		}
+	}
+	return ips, clients, nil
+}
+
+func PageShareFlag(shareFlagPageDto dto.ShareFlagPageDto) ([]entity.ShareFlag, int64, error) {
+	return dao.PageShareFlag(shareFlagPageDto)
+}
+
+// shareNetworkKey 按网段计数时 IPv4 取 /24，IPv6 取 /64
+func shareNetworkKey(ip string, network string) string {
+	if network != constant.ShareNetworkNetwork {
+		return ip
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ip
+	}
+	if ipv4 := parsed.To4(); ipv4 != nil {
+		return (&net.IPNet{IP: ipv4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String()
+	}
+	return (&net.IPNet{IP: parsed.Mask(net.CIDRMask(64, 128)), Mask: net.CIDRMask(64, 128)}).String()
+}
+
+// shareDetect 统计窗口内每个账户的不同 IP 或网段，返回超过 maxIps 的账户
+func shareDetect(logs []entity.SubscribeLog, network string, maxIps int) map[int64][]string {
+	keys := map[int64]map[string]struct{}{}
+	for _, item := range logs {
+		if keys[*item.AccountId] == nil {
+			keys[*item.AccountId] = map[string]struct{}{}
+		}
+		keys[*item.AccountId][shareNetworkKey(*item.Ip, network)] = struct{}{}
+	}
+	flagged := map[int64][]string{}
+	for accountId, set := range keys {
+		if len(set) <= maxIps {
+			continue
+		}
+		var ips []string
+		for key := range set {
+			ips = append(ips, key)
+		}
+		sort.Strings(ips)
+		flagged[accountId] = ips
+	}
+	return flagged
+}
+
+func getShareDetectConfig() (map[string]string, error) {
+	configs, err := dao.ListConfig("key in ?", []string{
+		constant.SubscribeLogDays,
+		constant.ShareDetectEnable,
+		constant.ShareDetectMaxIps,
+		constant.ShareDetectWindow,
+		constant.ShareDetectNetwork,
+		constant.ShareDetectAction,
+		constant.ShareDetectKickMinutes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]string{}
+	for _, item := range configs {
+		values[*item.Key] = *item.Value
+	}
+	return values, nil
+}
+
+// CronShareDetect 清理过期的访问记录并检测账户共享
+func CronShareDetect() {
+	if !shareDetectMutex.TryLock() {
+		return
+	}
+	defer shareDetectMutex.Unlock()
+
+	now := time.Now()
+	authLogMutex.Lock()
+	for key, at := range authLogAt {
+		if now.UnixMilli()-at >= authLogInterval.Milliseconds() {
+			delete(authLogAt, key)
+		}
+	}
+	authLogMutex.Unlock()
+
+	configs, err := getShareDetectConfig()
+	if err != nil {
+		return
+	}
+	if days, _ := strconv.ParseInt(configs[constant.SubscribeLogDays], 10, 64); days > 0 {
+		startAt := now.Add(-time.Duration(days) * 24 * time.Hour).UnixMilli()
+		_ = dao.DeleteSubscribeLog("access_at < ?", startAt)
+		_ = dao.DeleteShareFlag("flag_at < ?", startAt)
+	}
+	maxIps, _ := strconv.Atoi(configs[constant.ShareDetectMaxIps])
+	window, _ := strconv.ParseInt(configs[constant.ShareDetectWindow], 10, 64)
+	if configs[constant.ShareDetectEnable] != "1" || maxIps <= 0 || window <= 0 {
+		shareFlagAt = map[int64]int64{}
+		return
+	}
+	windowAt := now.Add(-time.Duration(window) * time.Minute).UnixMilli()
+	// 窗口之前处理过的账户不再需要记录
+	for accountId, at := range shareFlagAt {
+		if at < windowAt {
+			delete(shareFlagAt, accountId)
+		}
+	}
+	logs, err := dao.ListSubscribeLog("access_at >= ?", windowAt)
+	if err != nil {
+		return
+	}
+	for accountId, ips := range shareDetect(logs, configs[constant.ShareDetectNetwork], maxIps) {
+		// 上次处理后窗口内的记录仍会命中，窗口滑过上次处理时间之前不重复处理
+		if shareFlagAt[accountId] >= windowAt {
+			continue
+		}
+		shareFlagAt[accountId] = now.UnixMilli()
+		kickMinutes, _ := strconv.ParseInt(configs[constant.ShareDetectKickMinutes], 10, 64)
+		shareFlagged(accountId, ips, configs[constant.ShareDetectAction], kickMinutes)
+	}
+}
+
+func shareFlagged(accountId int64, ips []string, action string, kickMinutes int64) {
+	account, err := dao.GetAccount("id = ?", accountId)
+	if err != nil {
+		return
+	}
+	now := time.Now().UnixMilli()
+	ipCount := int64(len(ips))
+	ipsStr := strings.Join(ips, ",")
+	if _, err = dao.SaveShareFlag(entity.ShareFlag{
+		AccountId: &accountId,
+		IpCount:   &ipCount,
+		Ips:       &ipsStr,
+		Action:    &action,
+		FlagAt:    &now,
+	}); err != nil {
+		return
+	}
+
+	switch action {
+	case constant.ShareActionNotify:
+		text := fmt.Sprintf("%s may be shared, %d distinct addresses: %s", *account.Username, ipCount, ipsStr)
+		if err = Notify(constant.NotifyEventSharing, text); err != nil && err != ErrNotifyNoChannel {
+			logrus.Warnf("notify sharing err: %v", err)
+		}
+	case constant.ShareActionRotate:
+		if err = rotateConPass(account); err != nil {
+			logrus.Errorf("rotate %s connection password err: %v", *account.Username, err)
+		}
+	case constant.ShareActionKick:
+		if kickMinutes <= 0 {
+			kickMinutes = 10
+		}
+		kickUtilTime := time.Now().Add(time.Duration(kickMinutes) * time.Minute).UnixMilli()
+		if err = hysteria2Kick([]int64{accountId}, kickUtilTime, "sharing"); err != nil {
+			logrus.Errorf("kick %s err: %v", *account.Username, err)
+		}
+	}
+}
+
+// rotateConPass 重新生成连接密码，旧的订阅链接随之失效，在线连接会被断开
+func rotateConPass(account entity.Account) error {
+	conPassSuffix, err := util.RandomString(12)
+	if err != nil {
+		return err
+	}
+	// 通过 UpdateAccount 更新，与面板修改连接密码一致
+	if err = UpdateAccount(entity.Account{
+		BaseEntity: entity.BaseEntity{Id: account.Id},
+		Username:   account.Username,
+		ConPass:    &conPassSuffix,
+	}); err != nil {
+		return err
+	}
+	if Hysteria2IsRunning() {
+		// 踢下线后客户端重新认证，旧密码不再通过，不改变已有的禁止时间
+		if err = hysteria2Kick([]int64{*account.Id}, max(*account.KickUtilTime, time.Now().UnixMilli()), "sharing"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"h-ui/model/constant"
+	"h-ui/model/entity"
+	"reflect"
+	"testing"
+)
+
+func TestShareDetect(t *testing.T) {
+	var logs []entity.SubscribeLog
+	add := func(accountId int64, ip string) {
+		logs = append(logs, entity.SubscribeLog{AccountId: &accountId, Ip: &ip})
+	}
+	add(1, "203.0.113.1")
+	add(1, "203.0.113.2")
+	add(1, "203.0.113.2")
+	add(1, "2001:db8::1")
+	add(1, "2001:db8::2")
+	add(2, "198.51.100.1")
+
+	flagged := shareDetect(logs, constant.ShareNetworkIp, 3)
+	if want := []string{"2001:db8::1", "2001:db8::2", "203.0.113.1", "203.0.113.2"}; !reflect.DeepEqual(flagged[1], want) {
+		t.Fatalf("ip: got %v, want %v", flagged[1], want)
+	}
+	if _, ok := flagged[2]; ok {
+		t.Fatalf("account 2 should not be flagged")
+	}
+
+	flagged = shareDetect(logs, constant.ShareNetworkNetwork, 1)
+	if want := []string{"2001:db8::/64", "203.0.113.0/24"}; !reflect.DeepEqual(flagged[1], want) {
+		t.Fatalf("network: got %v, want %v", flagged[1], want)
+	}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"fmt"
+	"h-ui/model/bo"
+	"strings"
+)
+
+func loonProxy(node bo.Hysteria2Node) (string, error) {
+	params := []string{
+		fmt.Sprintf("%s = Hysteria2", lineProxyName(node.Name)),
+		node.Server,
+		node.Port,
+		fmt.Sprintf("\"%s\"", node.Password),
+	}
+	if node.Sni != "" {
+		params = append(params, fmt.Sprintf("sni=%s", node.Sni))
+	}
+	params = append(params, fmt.Sprintf("skip-cert-verify=%t", node.Insecure))
+	downMbps, err := bandwidthMbps(node.Down)
+	if err != nil {
+		return "", err
+	}
+	if downMbps > 0 {
+		params = append(params, fmt.Sprintf("download-bandwidth=%d", downMbps))
+	}
+	if node.ObfsPassword != "" {
+		params = append(params, fmt.Sprintf("salamander-password=%s", node.ObfsPassword))
+	}
+	params = append(params, "udp=true")
+	return strings.Join(params, ","), nil
+}
+
+// loonSubscribe Loon 节点订阅，每行一个节点
+func loonSubscribe(nodes []bo.Hysteria2Node) (string, error) {
+	var proxies []string
+	for _, node := range nodes {
+		proxy, err := loonProxy(node)
+		if err != nil {
+			return "", err
+		}
+		proxies = append(proxies, proxy)
+	}
+	return strings.Join(proxies, "\n") + "\n", nil
+}
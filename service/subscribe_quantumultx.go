@@ -0,0 +1,35 @@
+package service
+
+import (
+	"fmt"
+	"h-ui/model/bo"
+	"net"
+	"strings"
+)
+
+func quantumultXProxy(node bo.Hysteria2Node) string {
+	params := []string{
+		fmt.Sprintf("hysteria2=%s", net.JoinHostPort(node.Server, node.Port)),
+		fmt.Sprintf("password=%s", node.Password),
+	}
+	if node.ObfsPassword != "" {
+		params = append(params, "obfs=salamander", fmt.Sprintf("obfs-password=%s", node.ObfsPassword))
+	}
+	if node.Sni != "" {
+		params = append(params, fmt.Sprintf("tls-host=%s", node.Sni))
+	}
+	params = append(params,
+		fmt.Sprintf("tls-verification=%t", !node.Insecure),
+		"udp-relay=true",
+		fmt.Sprintf("tag=%s", lineProxyName(node.Name)))
+	return strings.Join(params, ", ")
+}
+
+// quantumultXSubscribe Quantumult X 的 server_remote 资源，每行一个节点
+func quantumultXSubscribe(nodes []bo.Hysteria2Node) string {
+	var proxies []string
+	for _, node := range nodes {
+		proxies = append(proxies, quantumultXProxy(node))
+	}
+	return strings.Join(proxies, "\n") + "\n"
+}
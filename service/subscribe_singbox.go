@@ -0,0 +1,98 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"h-ui/model/bo"
+	"strconv"
+)
+
+func singBoxOutbound(node bo.Hysteria2Node) (bo.SingBoxOutbound, error) {
+	port, err := strconv.ParseInt(node.Port, 10, 64)
+	if err != nil {
+		return bo.SingBoxOutbound{}, fmt.Errorf("invalid port %s", node.Port)
+	}
+	outbound := bo.SingBoxOutbound{
+		Type:       "hysteria2",
+		Tag:        node.Name,
+		Server:     node.Server,
+		ServerPort: port,
+		Password:   node.Password,
+		Tls: &bo.SingBoxTls{
+			Enabled:    true,
+			ServerName: node.Sni,
+			Insecure:   node.Insecure,
+		},
+	}
+	ranges, err := portRanges(node.Ports)
+	if err != nil {
+		return bo.SingBoxOutbound{}, err
+	}
+	for _, item := range ranges {
+		outbound.ServerPorts = append(outbound.ServerPorts, fmt.Sprintf("%d:%d", item[0], item[1]))
+	}
+	if outbound.UpMbps, err = bandwidthMbps(node.Up); err != nil {
+		return bo.SingBoxOutbound{}, err
+	}
+	if outbound.DownMbps, err = bandwidthMbps(node.Down); err != nil {
+		return bo.SingBoxOutbound{}, err
+	}
+	if node.ObfsPassword != "" {
+		outbound.Obfs = &bo.SingBoxObfs{
+			Type:     "salamander",
+			Password: node.ObfsPassword,
+		}
+	}
+	return outbound, nil
+}
+
+// singBoxSubscribe 生成完整的 sing-box 配置，NekoBox 只读取其中的 outbounds
+func singBoxSubscribe(nodes []bo.Hysteria2Node) (string, error) {
+	selector := bo.SingBoxOutbound{
+		Type: "selector",
+		Tag:  "PROXY",
+	}
+	var outbounds []bo.SingBoxOutbound
+	for _, node := range nodes {
+		outbound, err := singBoxOutbound(node)
+		if err != nil {
+			return "", err
+		}
+		selector.Outbounds = append(selector.Outbounds, node.Name)
+		outbounds = append(outbounds, outbound)
+	}
+	config := bo.SingBoxConfig{
+		Log: bo.SingBoxLog{
+			Level:     "warn",
+			Timestamp: true,
+		},
+		Inbounds: []bo.SingBoxInbound{
+			{
+				Type:        "tun",
+				Tag:         "tun-in",
+				Address:     []string{"172.19.0.1/30", "fdfe:dcba:9876::1/126"},
+				AutoRoute:   true,
+				StrictRoute: true,
+			},
+			{
+				Type:       "mixed",
+				Tag:        "mixed-in",
+				Listen:     "127.0.0.1",
+				ListenPort: 2080,
+			},
+		},
+		Outbounds: append(append([]bo.SingBoxOutbound{selector}, outbounds...), bo.SingBoxOutbound{
+			Type: "direct",
+			Tag:  "direct",
+		}),
+		Route: bo.SingBoxRoute{
+			AutoDetectInterface: true,
+			Final:               "PROXY",
+		},
+	}
+	configJson, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(configJson), nil
+}
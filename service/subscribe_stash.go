@@ -0,0 +1,55 @@
+package service
+
+import (
+	"gopkg.in/yaml.v3"
+	"h-ui/model/bo"
+)
+
+func stashProxy(node bo.Hysteria2Node) (bo.StashHysteria2, error) {
+	proxy := bo.StashHysteria2{
+		Name:           node.Name,
+		Type:           "hysteria2",
+		Server:         node.Server,
+		Port:           node.Port,
+		Auth:           node.Password,
+		Sni:            node.Sni,
+		SkipCertVerify: node.Insecure,
+	}
+	var err error
+	if proxy.UpSpeed, err = bandwidthMbps(node.Up); err != nil {
+		return bo.StashHysteria2{}, err
+	}
+	if proxy.DownSpeed, err = bandwidthMbps(node.Down); err != nil {
+		return bo.StashHysteria2{}, err
+	}
+	if node.ObfsPassword != "" {
+		proxy.Obfs = "salamander"
+		proxy.ObfsPassword = node.ObfsPassword
+	}
+	return proxy, nil
+}
+
+// stashSubscribe Stash 兼容 Clash 配置，只有代理的字段不同
+func stashSubscribe(nodes []bo.Hysteria2Node) (string, error) {
+	proxyGroup := bo.ProxyGroup{
+		Name: "PROXY",
+		Type: "select",
+	}
+	var proxies []interface{}
+	for _, node := range nodes {
+		proxy, err := stashProxy(node)
+		if err != nil {
+			return "", err
+		}
+		proxies = append(proxies, proxy)
+		proxyGroup.Proxies = append(proxyGroup.Proxies, node.Name)
+	}
+	stashConfigYaml, err := yaml.Marshal(&bo.ClashConfig{
+		Proxies:     proxies,
+		ProxyGroups: []bo.ProxyGroup{proxyGroup},
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(stashConfigYaml), nil
+}
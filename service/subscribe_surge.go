@@ -0,0 +1,72 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"h-ui/model/bo"
+	"strings"
+)
+
+// surgeProxy Surge 的 hysteria2 代理行，Surge 不支持 salamander 混淆，调用方需要先跳过混淆节点
+func surgeProxy(node bo.Hysteria2Node) (string, error) {
+	params := []string{fmt.Sprintf("password=%s", node.Password)}
+	downMbps, err := bandwidthMbps(node.Down)
+	if err != nil {
+		return "", err
+	}
+	if downMbps > 0 {
+		params = append(params, fmt.Sprintf("download-bandwidth=%d", downMbps))
+	}
+	if node.Sni != "" {
+		params = append(params, fmt.Sprintf("sni=%s", node.Sni))
+	}
+	params = append(params, fmt.Sprintf("skip-cert-verify=%t", node.Insecure))
+	if node.PinSHA256 != "" {
+		params = append(params, fmt.Sprintf("server-cert-fingerprint-sha256=%s", node.PinSHA256))
+	}
+	if node.Ports != "" {
+		ranges, err := portRanges(node.Ports)
+		if err != nil {
+			return "", err
+		}
+		var ports []string
+		for _, item := range ranges {
+			if item[0] == item[1] {
+				ports = append(ports, fmt.Sprintf("%d", item[0]))
+			} else {
+				ports = append(ports, fmt.Sprintf("%d-%d", item[0], item[1]))
+			}
+		}
+		params = append(params, fmt.Sprintf("port-hopping=\"%s\"", strings.Join(ports, ";")))
+	}
+	return fmt.Sprintf("%s = hysteria2, %s, %s, %s", lineProxyName(node.Name), node.Server, node.Port, strings.Join(params, ", ")), nil
+}
+
+// surgeSubscribe 生成 Surge 托管配置，subscribeUrl 用于自动更新
+// 使用 salamander 混淆的节点在 Surge 中无法连接，跳过并记录日志
+func surgeSubscribe(nodes []bo.Hysteria2Node, subscribeUrl string) (string, error) {
+	var proxies, names []string
+	for _, node := range nodes {
+		if node.ObfsPassword != "" {
+			logrus.Warnf("surge does not support salamander obfs, skip node %s", node.Name)
+			continue
+		}
+		proxy, err := surgeProxy(node)
+		if err != nil {
+			return "", err
+		}
+		proxies = append(proxies, proxy)
+		names = append(names, lineProxyName(node.Name))
+	}
+	if len(proxies) == 0 {
+		return "", errors.New("surge does not support salamander obfs, no node is available")
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "#!MANAGED-CONFIG %s interval=43200 strict=false\n\n", subscribeUrl)
+	b.WriteString("[General]\nloglevel = notify\n\n")
+	fmt.Fprintf(&b, "[Proxy]\n%s\n\n", strings.Join(proxies, "\n"))
+	fmt.Fprintf(&b, "[Proxy Group]\nPROXY = select, %s\n\n", strings.Join(names, ", "))
+	b.WriteString("[Rule]\nFINAL,PROXY\n")
+	return b.String(), nil
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"flag"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"h-ui/model/bo"
+	"h-ui/model/constant"
+	"h-ui/model/entity"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+// testSubscribeNodes 订阅格式测试共用的节点
+var testSubscribeNodes = map[string][]bo.Hysteria2Node{
+	"full": {{
+		Name:         "hui-full",
+		Server:       "example.com",
+		Port:         "443",
+		Ports:        "20000-30000,40000",
+		Password:     "conPass",
+		Up:           "100 mbps",
+		Down:         "1 gbps",
+		ObfsPassword: "obfsPass",
+		Sni:          "sni.example.com",
+	}},
+	"pinned": {{
+		Name:      "hui-pinned",
+		Server:    "1.2.3.4",
+		Port:      "443",
+		Password:  "conPass",
+		Insecure:  true,
+		PinSHA256: "4f3c2b1a00112233445566778899aabbccddeeff00112233445566778899aabb",
+	}},
+	"minimal": {{
+		Name:     "hui",
+		Server:   "1.2.3.4",
+		Port:     "8443",
+		Password: "conPass",
+	}},
+	// 节点名包含 Surge、Loon 和 Quantumult X 的分隔符
+	"special": {{
+		Name:     "HK, 01=fast",
+		Server:   "1.2.3.4",
+		Port:     "443",
+		Password: "conPass",
+	}},
+	"multi": {{
+		Name:     "hui",
+		Server:   "1.2.3.4",
+		Port:     "443",
+		Password: "conPass",
+		Down:     "200 mbps",
+	}, {
+		Name:         "tokyo",
+		Server:       "2001:db8::1",
+		Port:         "8443",
+		Ports:        "30000-31000",
+		Password:     "conPass",
+		ObfsPassword: "obfsPass",
+		Sni:          "jp.example.com",
+	}},
+}
+
+// assertGolden 和 testdata/subscribe 下的文件比较，使用 -update 重新生成
+func assertGolden(t *testing.T, name string, actual string) {
+	t.Helper()
+	path := filepath.Join("testdata", "subscribe", name)
+	if *updateGolden {
+		if err := os.WriteFile(path, []byte(actual), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(expected) != actual {
+		t.Fatalf("%s mismatch\nexpected:\n%s\nactual:\n%s", name, expected, actual)
+	}
+}
+
+// testSubscribeFormats 各订阅格式的生成函数和 golden 文件前缀、后缀，unsupported 为应当返回错误的节点集
+var testSubscribeFormats = []struct {
+	prefix      string
+	ext         string
+	generate    func(nodes []bo.Hysteria2Node) (string, error)
+	unsupported []string
+}{
+	{"clash_", ".yaml", func(nodes []bo.Hysteria2Node) (string, error) {
+		return clashSubscribe(nodes, false)
+	}, nil},
+	{"shadowrocket_", ".yaml", func(nodes []bo.Hysteria2Node) (string, error) {
+		return clashSubscribe(nodes, true)
+	}, nil},
+	{"singbox_", ".json", singBoxSubscribe, nil},
+	{"stash_", ".yaml", stashSubscribe, nil},
+	{"surge_", ".conf", func(nodes []bo.Hysteria2Node) (string, error) {
+		return surgeSubscribe(nodes, "https://example.com/hui/conPass")
+	}, []string{"full"}},
+	{"loon_", ".list", loonSubscribe, nil},
+	{"quantumultx_", ".list", func(nodes []bo.Hysteria2Node) (string, error) {
+		return quantumultXSubscribe(nodes), nil
+	}, nil},
+	{"hysteria2_", ".yaml", func(nodes []bo.Hysteria2Node) (string, error) {
+		return hysteria2ClientSubscribe(nodes[len(nodes)-1], "127.0.0.1:1080", "")
+	}, nil},
+	{"url_", ".txt", func(nodes []bo.Hysteria2Node) (string, error) {
+		return urlSubscribe(nodes), nil
+	}, nil},
+}
+
+func TestSubscribeFormats(t *testing.T) {
+	for _, format := range testSubscribeFormats {
+		for name, nodes := range testSubscribeNodes {
+			t.Run(format.prefix+name, func(t *testing.T) {
+				configStr, err := format.generate(nodes)
+				if slices.Contains(format.unsupported, name) {
+					if err == nil {
+						t.Fatal("expected unsupported error")
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				assertGolden(t, format.prefix+name+format.ext, configStr)
+			})
+		}
+	}
+}
+
+func TestBandwidthMbps(t *testing.T) {
+	cases := map[string]int64{
+		"":         0,
+		"100 mbps": 100,
+		"100m":     100,
+		"1 gbps":   1000,
+		"1.5g":     1500,
+		"500 kbps": 1,
+		"50000000": 50,
+	}
+	for bandwidth, expected := range cases {
+		actual, err := bandwidthMbps(bandwidth)
+		if err != nil || actual != expected {
+			t.Errorf("%q: expected %d, got %d %v", bandwidth, expected, actual, err)
+		}
+	}
+	if _, err := bandwidthMbps("100 furlongs"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestSubscribeUaRules(t *testing.T) {
+	// 使用 h_ui_db.sql 初始化的默认规则
+	cases := map[string]string{
+		"NekoBox/Android/1.3.4 (Prefer ClashMeta Format)": constant.NekoBox,
+		"SFA/1.9.0 (409; sing-box 1.9.0)":                 constant.SingBox,
+		"Stash/2.4.7 Clash/1.9.0":                         constant.Stash,
+		"Surge iOS/2920":                                  constant.Surge,
+		"Loon/731 CFNetwork/1410.0.3":                     constant.Loon,
+		"Quantumult%20X/1.4.1":                            constant.QuantumultX,
+		"Shadowrocket/2070 CFNetwork/1410.0.3":            constant.Shadowrocket,
+		"clash-verge/v1.3.8":                              constant.Clash,
+		"v2rayN/6.23":                                     constant.V2rayN,
+		"Mozilla/5.0":                                     constant.Clash,
+	}
+	for userAgent, expected := range cases {
+		actual, err := SubscribeClientType("", userAgent)
+		if err != nil || actual != expected {
+			t.Errorf("%s: expected %s, got %s %v", userAgent, expected, actual, err)
+		}
+	}
+	// 请求指定的格式优先于 User-Agent
+	if actual, err := SubscribeClientType("sing-box", "clash-verge/v1.3.8"); err != nil || actual != constant.SingBox {
+		t.Errorf("format: expected %s, got %s %v", constant.SingBox, actual, err)
+	}
+	if err := ValidSubscribeUaRules(`[{"pattern":"(","format":"clash"}]`); err == nil {
+		t.Error("expected invalid pattern error")
+	}
+	if err := ValidSubscribeUaRules(`[{"pattern":"foo","format":"bar"}]`); err == nil {
+		t.Error("expected unsupported format error")
+	}
+}
+
+func TestHysteria2NodeUrlEscape(t *testing.T) {
+	node := bo.Hysteria2Node{
+		Name:         "香港 #1/a",
+		Server:       "2001:db8::1",
+		Port:         "443",
+		Password:     "user.p@ss:w/rd",
+		ObfsPassword: "a&b=c#d",
+		Down:         "1 gbps",
+	}
+	nodeUrl, err := url.Parse(hysteria2NodeUrl(node))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nodeUrl.User.Username() != node.Password {
+		t.Fatalf("password %q", nodeUrl.User.Username())
+	}
+	if nodeUrl.Hostname() != node.Server || nodeUrl.Port() != node.Port {
+		t.Fatalf("host %q", nodeUrl.Host)
+	}
+	if nodeUrl.Fragment != node.Name {
+		t.Fatalf("name %q", nodeUrl.Fragment)
+	}
+	query := nodeUrl.Query()
+	if query.Get("obfs-password") != node.ObfsPassword || query.Get("downmbps") != node.Down {
+		t.Fatalf("query %v", query)
+	}
+
+	node.Name = ""
+	if strings.Contains(hysteria2NodeUrl(node), "#") {
+		t.Fatalf("empty name should not add a fragment")
+	}
+}
+
+func TestRenderClashTemplate(t *testing.T) {
+	nodes := testSubscribeNodes["multi"]
+	configYaml, err := clashSubscribe(nodes, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template, err := os.ReadFile(filepath.Join("testdata", "subscribe", "clash_template_input.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rendered, err := renderClashTemplate(configYaml, string(template), []string{nodes[0].Name, nodes[1].Name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "clash_template_output.yaml", rendered)
+
+	invalids := []string{
+		"- a\n- b\n",
+		"proxy-groups: PROXY\n",
+		"proxy-groups:\n  - type: select\n",
+		"proxies:\n  - name: hysteria2\n    type: socks5\n",
+	}
+	for _, item := range invalids {
+		if err = ValidClashTemplate(item); err == nil {
+			t.Errorf("expected error for %q", item)
+		}
+	}
+	if err = ValidClashTemplate(""); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSubscribeInfoNodes(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	newAccount := func(quota int64, used int64, expire time.Time, deleted int64) entity.Account {
+		username := "user1"
+		var upload int64 = 0
+		expireTime := expire.UnixMilli()
+		return entity.Account{Username: &username, Quota: &quota, Download: &used, Upload: &upload,
+			ExpireTime: &expireTime, Deleted: &deleted}
+	}
+	nodes := []bo.Hysteria2Node{{Name: "hui", Server: "1.2.3.4", Port: "443", Password: "conPass"}}
+	info := subscribeInfo{
+		enable:       true,
+		template:     "Remaining: {remaining}\nExpire: {expire} ({days} days)",
+		announcement: "Hello {username}",
+		notice:       "{username} is {status}",
+	}
+	names := func(nodes []bo.Hysteria2Node) string {
+		var names []string
+		for _, item := range nodes {
+			names = append(names, item.Name)
+		}
+		return strings.Join(names, "|")
+	}
+
+	active := newAccount(10*1024*1024*1024, 4*1024*1024*1024, now.AddDate(0, 1, 0), 0)
+	if actual := names(subscribeInfoNodes(active, nodes, info, now)); actual != "Remaining: 6.00 GB|Expire: 2024-02-01 (31 days)|Hello user1|hui" {
+		t.Errorf("active: %s", actual)
+	}
+	disabledInfo := info
+	disabledInfo.enable = false
+	if actual := names(subscribeInfoNodes(active, nodes, disabledInfo, now)); actual != "hui" {
+		t.Errorf("info disabled: %s", actual)
+	}
+
+	cases := map[string]entity.Account{
+		"user1 is expired|Hello user1":    newAccount(-1, 0, now.AddDate(0, 0, -1), 0),
+		"user1 is over quota|Hello user1": newAccount(1024, 2048, now.AddDate(0, 1, 0), 0),
+		"user1 is disabled|Hello user1":   newAccount(-1, 0, now.AddDate(0, 1, 0), 1),
+	}
+	for expected, account := range cases {
+		if actual := names(subscribeInfoNodes(account, nodes, disabledInfo, now)); actual != expected {
+			t.Errorf("expected %s, got %s", expected, actual)
+		}
+	}
+}
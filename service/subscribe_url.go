@@ -0,0 +1,58 @@
+package service
+
+import (
+	"h-ui/model/bo"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// hysteria2NodeUrl hysteria2:// 分享链接，peer、downmbps、mport 是 Shadowrocket 的参数
+func hysteria2NodeUrl(node bo.Hysteria2Node) string {
+	params := url.Values{}
+	if node.ObfsPassword != "" {
+		params.Set("obfs", "salamander")
+		params.Set("obfs-password", node.ObfsPassword)
+	}
+	if node.Sni != "" {
+		params.Set("sni", node.Sni)
+		// shadowrocket
+		params.Set("peer", node.Sni)
+	}
+	if node.Insecure {
+		params.Set("insecure", "1")
+	} else {
+		params.Set("insecure", "0")
+	}
+	if node.PinSHA256 != "" {
+		params.Set("pinSHA256", node.PinSHA256)
+	}
+	if node.Down != "" {
+		params.Set("downmbps", node.Down)
+	}
+	if node.Ports != "" {
+		params.Set("mport", node.Ports)
+	}
+	nodeUrl := url.URL{
+		Scheme: "hysteria2",
+		User:   url.User(node.Password),
+		Host:   net.JoinHostPort(node.Server, node.Port),
+		Path:   "/",
+		// shadowrocket 不把 + 解析为空格，空格使用 %20
+		RawQuery: strings.ReplaceAll(params.Encode(), "+", "%20"),
+	}
+	// 节点名为空时不带 #，与未设置备注时的分享链接一致
+	if node.Name != "" {
+		return nodeUrl.String() + "#" + url.PathEscape(node.Name)
+	}
+	return nodeUrl.String()
+}
+
+// urlSubscribe v2rayN 订阅，每行一个分享链接，由调用方进行 base64 编码
+func urlSubscribe(nodes []bo.Hysteria2Node) string {
+	var urls []string
+	for _, node := range nodes {
+		urls = append(urls, hysteria2NodeUrl(node))
+	}
+	return strings.Join(urls, "\n")
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+	"h-ui/dao"
+	"h-ui/model/bo"
+	"h-ui/model/entity"
+	"h-ui/model/vo"
+	"h-ui/util"
+	"math"
+	"sync"
+	"time"
+)
+
+// 每分钟采样一次，保留 7 天
+const systemMetricMaxRows = 7 * 24 * 60
+
+var systemMetricMutex sync.Mutex
+var lastNetCounters map[string]bo.NetCounter
+var lastNetTime time.Time
+
+func CronSystemMetric() {
+	if !systemMetricMutex.TryLock() {
+		return
+	}
+	defer systemMetricMutex.Unlock()
+
+	now := time.Now()
+	cpuPercent, err := util.GetCpuPercent()
+	if err != nil {
+		logrus.Errorf("system metric cpu query err: %v", err)
+		return
+	}
+	memPercent, err := util.GetMemPercent()
+	if err != nil {
+		logrus.Errorf("system metric mem query err: %v", err)
+		return
+	}
+	diskUsages, err := util.GetDiskUsages()
+	if err != nil {
+		logrus.Errorf("system metric disk query err: %v", err)
+	}
+	netCounters, err := util.GetNetCounters()
+	if err != nil {
+		logrus.Errorf("system metric net query err: %v", err)
+	}
+
+	var netRates []bo.NetRate
+	seconds := now.Sub(lastNetTime).Seconds()
+	counters := map[string]bo.NetCounter{}
+	for _, item := range netCounters {
+		counters[item.Name] = item
+		last, ok := lastNetCounters[item.Name]
+		if !ok || seconds <= 0 || item.BytesSent < last.BytesSent || item.BytesRecv < last.BytesRecv {
+			continue
+		}
+		netRates = append(netRates, bo.NetRate{
+			Name:     item.Name,
+			SentRate: float64(item.BytesSent-last.BytesSent) / seconds,
+			RecvRate: float64(item.BytesRecv-last.BytesRecv) / seconds,
+		})
+	}
+	lastNetCounters = counters
+	lastNetTime = now
+
+	var userTotal, deviceTotal int64
+	if onlineUsers, err := Hysteria2Online(); err == nil {
+		userTotal = int64(len(onlineUsers))
+		for _, value := range onlineUsers {
+			deviceTotal += value
+		}
+	}
+
+	disksJson, _ := json.Marshal(diskUsages)
+	netsJson, _ := json.Marshal(netRates)
+	disks := string(disksJson)
+	nets := string(netsJson)
+	sampleTime := now.UnixMilli()
+	if _, err = dao.SaveSystemMetric(entity.SystemMetric{
+		CpuPercent:  &cpuPercent,
+		MemPercent:  &memPercent,
+		Disks:       &disks,
+		Nets:        &nets,
+		UserTotal:   &userTotal,
+		DeviceTotal: &deviceTotal,
+		SampleTime:  &sampleTime,
+	}); err != nil {
+		return
+	}
+	_ = dao.TrimSystemMetric(systemMetricMaxRows)
+}
+
+// ListSystemMetric 按时间段查询，时间段越长聚合粒度越大
+func ListSystemMetric(period string) ([]vo.SystemMetricVo, error) {
+	duration, step := time.Hour, time.Minute
+	if period == "day" {
+		duration, step = 24*time.Hour, 5*time.Minute
+	} else if period == "week" {
+		duration, step = 7*24*time.Hour, 30*time.Minute
+	}
+	systemMetrics, err := dao.ListSystemMetric("sample_time >= ?", time.Now().Add(-duration).UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+
+	systemMetricVos := make([]vo.SystemMetricVo, 0)
+	var bucket []entity.SystemMetric
+	var bucketStart int64 = -1
+	for _, item := range systemMetrics {
+		start := *item.SampleTime - *item.SampleTime%step.Milliseconds()
+		if start != bucketStart && len(bucket) > 0 {
+			systemMetricVos = append(systemMetricVos, aggregateSystemMetric(bucketStart, bucket))
+			bucket = nil
+		}
+		bucketStart = start
+		bucket = append(bucket, item)
+	}
+	if len(bucket) > 0 {
+		systemMetricVos = append(systemMetricVos, aggregateSystemMetric(bucketStart, bucket))
+	}
+	return systemMetricVos, nil
+}
+
+func aggregateSystemMetric(start int64, systemMetrics []entity.SystemMetric) vo.SystemMetricVo {
+	systemMetricVo := vo.SystemMetricVo{Time: start}
+	var nets []string
+	netSums := map[string]*bo.NetRate{}
+	netCounts := map[string]float64{}
+	for _, item := range systemMetrics {
+		systemMetricVo.CpuPercent += *item.CpuPercent
+		systemMetricVo.MemPercent += *item.MemPercent
+		systemMetricVo.UserTotal += *item.UserTotal
+		systemMetricVo.DeviceTotal += *item.DeviceTotal
+
+		var disks []bo.DiskUsage
+		if err := json.Unmarshal([]byte(*item.Disks), &disks); err == nil && len(disks) > 0 {
+			systemMetricVo.Disks = disks
+		}
+
+		var netRates []bo.NetRate
+		if err := json.Unmarshal([]byte(*item.Nets), &netRates); err == nil {
+			for _, netRate := range netRates {
+				sum, ok := netSums[netRate.Name]
+				if !ok {
+					sum = &bo.NetRate{Name: netRate.Name}
+					netSums[netRate.Name] = sum
+					nets = append(nets, netRate.Name)
+				}
+				sum.SentRate += netRate.SentRate
+				sum.RecvRate += netRate.RecvRate
+				netCounts[netRate.Name]++
+			}
+		}
+	}
+
+	count := float64(len(systemMetrics))
+	systemMetricVo.CpuPercent = math.Round(systemMetricVo.CpuPercent/count*10) / 10
+	systemMetricVo.MemPercent = math.Round(systemMetricVo.MemPercent/count*10) / 10
+	systemMetricVo.UserTotal = systemMetricVo.UserTotal / int64(len(systemMetrics))
+	systemMetricVo.DeviceTotal = systemMetricVo.DeviceTotal / int64(len(systemMetrics))
+	for _, name := range nets {
+		sum := netSums[name]
+		systemMetricVo.Nets = append(systemMetricVo.Nets, bo.NetRate{
+			Name:     name,
+			SentRate: sum.SentRate / netCounts[name],
+			RecvRate: sum.RecvRate / netCounts[name],
+		})
+	}
+	return systemMetricVo
+}
@@ -10,6 +10,7 @@ import (
 	"h-ui/model/constant"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -69,30 +70,21 @@ func InitTelegramBot() error {
 		return err
 	}
 	logrus.Infof("telegram token length=%d chatId=%s", len(token), chatId)
-	dnsServers := os.Getenv(constant.TelegramDNSServers)
-	if dnsServers != "" {
-		for _, s := range strings.Split(dnsServers, ",") {
-			addr := strings.TrimSpace(s)
-			if addr == "" {
-				continue
-			}
-			r := &net.Resolver{PreferGo: true, Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
-				d := net.Dialer{}
-				return d.DialContext(ctx, "udp", net.JoinHostPort(addr, "53"))
-			}}
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			_, err := r.LookupHost(ctx, "api.telegram.org")
-			cancel()
-			if err == nil {
-				net.DefaultResolver = r
-				logrus.Infof("telegram dns server %s", addr)
-				break
-			}
-		}
+	proxyConfig, err := dao.GetConfig("key = ?", constant.TelegramProxy)
+	if err != nil {
+		return err
+	}
+	apiEndpointConfig, err := dao.GetConfig("key = ?", constant.TelegramApiEndpoint)
+	if err != nil {
+		return err
+	}
+	apiEndpoint := TelegramApiEndpoint(*apiEndpointConfig.Value)
+	httpClient, err := telegramHttpClient(*proxyConfig.Value, os.Getenv(constant.TelegramDNSServers), apiEndpoint)
+	if err != nil {
+		return err
 	}
-	httpClient := &http.Client{Timeout: 15 * time.Second}
 	logrus.Info("creating telegram bot api")
-	bot, err = tgbotapi.NewBotAPIWithClient(token, tgbotapi.APIEndpoint, httpClient)
+	bot, err = tgbotapi.NewBotAPIWithClient(token, apiEndpoint, httpClient)
 	if err != nil {
 		logrus.Errorf("telegram init failed: %v", err)
 		return fmt.Errorf("telegram init failed: %w", err)
@@ -104,11 +96,28 @@ func InitTelegramBot() error {
 	commands := []tgbotapi.BotCommand{
 		{Command: "status", Description: "System Status"},
 		{Command: "restart", Description: "System Restart"},
+		{Command: "user", Description: "Account usage: /user <username>"},
+		{Command: "online", Description: "Online users"},
+		{Command: "kick", Description: "Kick: /kick <username> [minutes]"},
+		{Command: "reset", Description: "Reset traffic: /reset <username>"},
+		{Command: "extend", Description: "Extend expiry: /extend <username> <days>"},
+		{Command: "enable", Description: "Enable account: /enable <username>"},
+		{Command: "disable", Description: "Disable account: /disable <username>"},
+		{Command: "create", Description: "Create: /create <username> [quotaGB] [days] [devices]"},
+		{Command: "bindcode", Description: "User binding code: /bindcode <username>"},
 	}
+	setCommands := tgbotapi.NewSetMyCommands(commands...)
 	if chatId == "" {
 		commands = append(commands, tgbotapi.BotCommand{Command: "chatid", Description: "Get chatId"})
+		setCommands = tgbotapi.NewSetMyCommands(commands...)
+	} else if adminChatId, err := strconv.ParseInt(chatId, 10, 64); err == nil {
+		// 管理员会话显示管理命令，其他会话显示用户命令
+		setCommands = tgbotapi.NewSetMyCommandsWithScope(tgbotapi.NewBotCommandScopeChat(adminChatId), commands...)
+		if _, err := bot.Request(tgbotapi.NewSetMyCommands(telegramUserCommands...)); err != nil {
+			logrus.Errorf("unable to set user commands err: %v", err)
+			return err
+		}
 	}
-	setCommands := tgbotapi.NewSetMyCommands(commands...)
 	if _, err := bot.Request(setCommands); err != nil {
 		logrus.Errorf("unable to set commands err: %v", err)
 		return err
@@ -132,6 +141,72 @@ func InitTelegramBot() error {
 	return nil
 }
 
+// TelegramApiEndpoint 自定义 Bot API 地址，例如本地 Bot API 服务 http://127.0.0.1:8081
+func TelegramApiEndpoint(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return tgbotapi.APIEndpoint
+	}
+	if strings.Contains(value, "%s") {
+		return value
+	}
+	return strings.TrimRight(value, "/") + "/bot%s/%s"
+}
+
+// ValidTelegramProxy 支持 http、https 和 socks5 代理
+func ValidTelegramProxy(value string) error {
+	if value == "" {
+		return nil
+	}
+	proxyUrl, err := url.Parse(value)
+	if err != nil || proxyUrl.Host == "" {
+		return errors.New("telegram proxy is invalid")
+	}
+	if proxyUrl.Scheme != "http" && proxyUrl.Scheme != "https" && proxyUrl.Scheme != "socks5" {
+		return errors.New("telegram proxy only supports http, https and socks5")
+	}
+	return nil
+}
+
+// telegramHttpClient 代理和 DNS 只作用于机器人自己的连接
+func telegramHttpClient(proxy string, dnsServers string, apiEndpoint string) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxy != "" {
+		if err := ValidTelegramProxy(proxy); err != nil {
+			return nil, err
+		}
+		proxyUrl, _ := url.Parse(proxy)
+		transport.Proxy = http.ProxyURL(proxyUrl)
+		logrus.Infof("telegram proxy %s://%s", proxyUrl.Scheme, proxyUrl.Host)
+	}
+	if dnsServers != "" {
+		host := "api.telegram.org"
+		if endpoint, err := url.Parse(strings.ReplaceAll(apiEndpoint, "%s", "x")); err == nil && endpoint.Hostname() != "" {
+			host = endpoint.Hostname()
+		}
+		for _, s := range strings.Split(dnsServers, ",") {
+			addr := strings.TrimSpace(s)
+			if addr == "" {
+				continue
+			}
+			r := &net.Resolver{PreferGo: true, Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+				d := net.Dialer{}
+				return d.DialContext(ctx, "udp", net.JoinHostPort(addr, "53"))
+			}}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			_, err := r.LookupHost(ctx, host)
+			cancel()
+			if err == nil {
+				dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Resolver: r}
+				transport.DialContext = dialer.DialContext
+				logrus.Infof("telegram dns server %s", addr)
+				break
+			}
+		}
+	}
+	return &http.Client{Timeout: 15 * time.Second, Transport: transport}, nil
+}
+
 func getUpdatesChan() tgbotapi.UpdatesChannel {
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
@@ -139,6 +214,20 @@ func getUpdatesChan() tgbotapi.UpdatesChannel {
 }
 
 func handleMsg(update tgbotapi.Update, chatId string) {
+	if update.CallbackQuery != nil && update.CallbackQuery.Message != nil &&
+		chatId != "" && strconv.FormatInt(update.CallbackQuery.Message.Chat.ID, 10) == chatId {
+		if err := handleCallback(update); err != nil {
+			logrus.Errorf("handleCallback err: %v", err)
+		}
+		return
+	}
+	if update.Message != nil && update.Message.IsCommand() &&
+		chatId != "" && strconv.FormatInt(update.Message.Chat.ID, 10) != chatId {
+		if err := handleUserCommand(update); err != nil {
+			logrus.Errorf("handleUserCommand err: %v", err)
+		}
+		return
+	}
 	if update.Message != nil && update.Message.IsCommand() && (chatId == "" || strconv.FormatInt(update.Message.Chat.ID, 10) == chatId) {
 		switch update.Message.Command() {
 		case "chatid":
@@ -153,6 +242,13 @@ func handleMsg(update tgbotapi.Update, chatId string) {
 			if err := handleRestart(update); err != nil {
 				logrus.Errorf("handleRestart err: %v", err)
 			}
+		case "user", "online", "kick", "reset", "extend", "enable", "disable", "create", "bindcode":
+			if chatId == "" {
+				return
+			}
+			if err := handleAccountCommand(update); err != nil {
+				logrus.Errorf("handleAccountCommand err: %v", err)
+			}
 		default:
 			if err := handleDefault(update); err != nil {
 				logrus.Errorf("handleDefault err: %v", err)
@@ -240,6 +336,10 @@ func GetMe() (tgbotapi.User, error) {
 }
 
 func SendWithMessage(chatId int64, text string) error {
+	return sendChattable(chatId, text, tgbotapi.NewMessage(chatId, text))
+}
+
+func sendChattable(chatId int64, text string, c tgbotapi.Chattable) error {
 	if bot == nil {
 		return fmt.Errorf("telegram init failed")
 	}
@@ -247,8 +347,7 @@ func SendWithMessage(chatId int64, text string) error {
 	defer cancel()
 	ch := make(chan error, 1)
 	go func() {
-		message := tgbotapi.NewMessage(chatId, text)
-		_, err := bot.Send(message)
+		_, err := bot.Request(c)
 		ch <- err
 	}()
 	select {
@@ -264,26 +363,49 @@ func SendWithMessage(chatId int64, text string) error {
 	}
 }
 
-// TelegramLoginRemind 登录提醒
-func telegramLoginRemind(username string, ip string) error {
+// TelegramNotifyAdmin 发送消息给管理员
+func TelegramNotifyAdmin(text string) error {
 	configs, err := dao.ListConfig("key in ?", []string{
 		constant.TelegramEnable,
-		constant.TelegramChatId,
+		constant.TelegramChatId})
+	if err != nil {
+		return err
+	}
+	var telegramEnable, telegramChatId = "0", ""
+	for _, item := range configs {
+		if item.Value != nil {
+			if *item.Key == constant.TelegramEnable {
+				telegramEnable = *item.Value
+			} else if *item.Key == constant.TelegramChatId {
+				telegramChatId = *item.Value
+			}
+		}
+	}
+	if telegramEnable != "1" || telegramChatId == "" {
+		return errors.New("telegram not enable")
+	}
+	chatId, err := strconv.ParseInt(telegramChatId, 10, 64)
+	if err != nil {
+		logrus.Errorf("parse chatId err: %v", err)
+		return err
+	}
+	return SendWithMessage(chatId, fmt.Sprintf("【H UI】\n%s", text))
+}
+
+// TelegramLoginRemind 登录提醒，按 login 事件路由发送
+func telegramLoginRemind(username string, ip string) error {
+	configs, err := dao.ListConfig("key in ?", []string{
 		constant.TelegramLoginJobEnable,
 		constant.TelegramLoginJobText})
 	if err != nil {
 		return err
 	}
-	var telegramEnable, telegramChatId, telegramLoginJobEnable, telegramLoginJobText = "0", "", "0", ""
+	var telegramLoginJobEnable, telegramLoginJobText = "0", ""
 	for _, item := range configs {
 		if item.Value != nil {
 			key := *item.Key
 			value := *item.Value
-			if key == constant.TelegramEnable {
-				telegramEnable = value
-			} else if key == constant.TelegramChatId {
-				telegramChatId = value
-			} else if key == constant.TelegramLoginJobEnable {
+			if key == constant.TelegramLoginJobEnable {
 				telegramLoginJobEnable = value
 			} else if key == constant.TelegramLoginJobText {
 				telegramLoginJobText = value
@@ -291,26 +413,33 @@ func telegramLoginRemind(username string, ip string) error {
 		}
 	}
 
-	if telegramEnable != "1" || telegramChatId == "" || telegramLoginJobEnable != "1" || telegramLoginJobText == "" {
+	if telegramLoginJobEnable != "1" || telegramLoginJobText == "" {
 		return nil
 	}
 
-	chatId, err := strconv.ParseInt(telegramChatId, 10, 64)
-	if err != nil {
-		logrus.Errorf("parse chatId err: %v", err)
-		return err
-	}
-
 	telegramLoginJobText = strings.ReplaceAll(telegramLoginJobText, "[time]", time.Now().Format("2006-01-02 15:04:05"))
 	telegramLoginJobText = strings.ReplaceAll(telegramLoginJobText, "[username]", username)
 	telegramLoginJobText = strings.ReplaceAll(telegramLoginJobText, "[ip]", ip)
 
-	if err = SendWithMessage(chatId, fmt.Sprintf("【H UI】\n%s", telegramLoginJobText)); err != nil {
-		return err
+	// 登录只等待有限的时间，超时后通知在后台继续发送
+	ch := make(chan error, 1)
+	go func() {
+		ch <- Notify(constant.NotifyEventLogin, telegramLoginJobText)
+	}()
+	select {
+	case err = <-ch:
+		if err != nil && err != ErrNotifyNoChannel {
+			return err
+		}
+		return nil
+	case <-time.After(loginRemindTimeout):
+		return errors.New("login notify timeout")
 	}
-	return nil
 }
 
+// loginRemindTimeout 登录通知的最长等待时间
+const loginRemindTimeout = 3 * time.Second
+
 var TelegramLoginRemind = telegramLoginRemind
 
 func telegram2FAEnabled(username string) bool {
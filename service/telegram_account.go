@@ -0,0 +1,339 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"h-ui/dao"
+	"h-ui/model/constant"
+	"h-ui/model/entity"
+	"h-ui/util"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var telegramUsernameRegexp = regexp.MustCompile("^[a-zA-Z0-9!@#$%^&*()_+-=]{6,32}$")
+
+func handleAccountCommand(update tgbotapi.Update) error {
+	chatId := update.Message.Chat.ID
+	args := strings.Fields(update.Message.CommandArguments())
+	var text string
+	var err error
+	switch update.Message.Command() {
+	case "user":
+		text, err = telegramAccountInfo(args)
+	case "online":
+		text, err = telegramOnline()
+	case "kick", "reset", "disable":
+		return telegramConfirm(chatId, update.Message.Command(), args)
+	case "extend":
+		text, err = telegramExtend(args)
+	case "enable":
+		text, err = telegramSetDeleted(args, 0)
+	case "create":
+		text, err = telegramCreate(args)
+	case "bindcode":
+		text, err = telegramAdminBindCode(args)
+	}
+	if err != nil {
+		text = err.Error()
+	}
+	return SendWithMessage(chatId, text)
+}
+
+// telegramConfirm 危险操作先发送确认按钮
+func telegramConfirm(chatId int64, command string, args []string) error {
+	if len(args) < 1 {
+		return SendWithMessage(chatId, fmt.Sprintf("usage: /%s <username>", command))
+	}
+	account, err := dao.GetAccount("username = ?", args[0])
+	if err != nil {
+		return SendWithMessage(chatId, fmt.Sprintf("account %s not exist", args[0]))
+	}
+	arg := "0"
+	if command == "kick" {
+		arg = "60"
+		if len(args) > 1 {
+			if _, err := strconv.ParseInt(args[1], 10, 64); err != nil {
+				return SendWithMessage(chatId, "minutes is invalid")
+			}
+			arg = args[1]
+		}
+	}
+	message := tgbotapi.NewMessage(chatId, fmt.Sprintf("Confirm /%s %s?", command, *account.Username))
+	message.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("Confirm", fmt.Sprintf("%s|%d|%s", command, *account.Id, arg)),
+		tgbotapi.NewInlineKeyboardButtonData("Cancel", "cancel"),
+	))
+	return sendChattable(chatId, message.Text, message)
+}
+
+func handleCallback(update tgbotapi.Update) error {
+	callback := update.CallbackQuery
+	chatId := callback.Message.Chat.ID
+	_, _ = bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+
+	text := "Cancelled"
+	parts := strings.Split(callback.Data, "|")
+	if len(parts) == 3 {
+		var err error
+		if parts[0] == "login" {
+			text, err = telegramLoginDecide(parts[1], parts[2])
+		} else {
+			text, err = telegramExecute(parts[0], parts[1], parts[2])
+		}
+		if err != nil {
+			text = err.Error()
+		}
+	}
+	edit := tgbotapi.NewEditMessageText(chatId, callback.Message.MessageID, text)
+	return sendChattable(chatId, text, edit)
+}
+
+func telegramExecute(command string, idStr string, arg string) (string, error) {
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return "", errors.New("account id is invalid")
+	}
+	account, err := dao.GetAccount("id = ?", id)
+	if err != nil {
+		return "", errors.New("account not exist")
+	}
+	switch command {
+	case "kick":
+		minutes, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return "", errors.New("minutes is invalid")
+		}
+		kickUtilTime := time.Now().Add(time.Duration(minutes) * time.Minute).UnixMilli()
+		if err = Hysteria2Kick([]int64{id}, kickUtilTime); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s kicked for %d minutes", *account.Username, minutes), nil
+	case "reset":
+		if err = ResetTraffic(id); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s traffic reset", *account.Username), nil
+	case "disable":
+		return telegramSetDeleted([]string{*account.Username}, 1)
+	}
+	return "", errors.New("unsupported command")
+}
+
+func telegramAccountInfo(args []string) (string, error) {
+	if len(args) < 1 {
+		return "", errors.New("usage: /user <username>")
+	}
+	account, err := dao.GetAccount("username = ?", args[0])
+	if err != nil {
+		return "", fmt.Errorf("account %s not exist", args[0])
+	}
+	onlineUsers, err := Hysteria2Online()
+	if err != nil {
+		return "", err
+	}
+	return telegramAccountText(account, onlineUsers[*account.Username]), nil
+}
+
+func telegramAccountText(account entity.Account, device int64) string {
+	used := *account.Download + *account.Upload
+	quota := "Unlimited"
+	remaining := "Unlimited"
+	if *account.Quota >= 0 {
+		quota = util.FormatBytes(*account.Quota)
+		remaining = util.FormatBytes(max(*account.Quota-used, 0))
+	}
+	status := "Enabled"
+	if *account.Deleted != 0 {
+		status = "Disabled"
+	}
+	text := fmt.Sprintf("Username: %s\n", *account.Username)
+	text += fmt.Sprintf("Status: %s\n", status)
+	text += fmt.Sprintf("Upload: %s\n", util.FormatBytes(*account.Upload))
+	text += fmt.Sprintf("Download: %s\n", util.FormatBytes(*account.Download))
+	text += fmt.Sprintf("Quota: %s\n", quota)
+	text += fmt.Sprintf("Remaining: %s\n", remaining)
+	text += fmt.Sprintf("Expire Time: %s\n", time.UnixMilli(*account.ExpireTime).Format("2006-01-02 15:04:05"))
+	text += fmt.Sprintf("Online Devices: %d/%d\n", device, *account.DeviceNo)
+	return text
+}
+
+func telegramOnline() (string, error) {
+	onlineUsers, err := Hysteria2Online()
+	if err != nil {
+		return "", err
+	}
+	if len(onlineUsers) == 0 {
+		return "No online users", nil
+	}
+	usernames := make([]string, 0, len(onlineUsers))
+	for username := range onlineUsers {
+		usernames = append(usernames, username)
+	}
+	sort.Strings(usernames)
+	text := fmt.Sprintf("Online users: %d\n", len(usernames))
+	for _, username := range usernames {
+		text += fmt.Sprintf("%s: %d devices\n", username, onlineUsers[username])
+	}
+	return text, nil
+}
+
+func telegramExtend(args []string) (string, error) {
+	if len(args) < 2 {
+		return "", errors.New("usage: /extend <username> <days>")
+	}
+	days, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil || days <= 0 {
+		return "", errors.New("days is invalid")
+	}
+	account, err := dao.GetAccount("username = ?", args[0])
+	if err != nil {
+		return "", fmt.Errorf("account %s not exist", args[0])
+	}
+	// 已过期的从当前时间开始计算
+	expireTime := max(*account.ExpireTime, time.Now().UnixMilli()) + days*24*time.Hour.Milliseconds()
+	if err = UpdateAccount(entity.Account{
+		BaseEntity: entity.BaseEntity{Id: account.Id},
+		ExpireTime: &expireTime,
+	}); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s expires at %s", *account.Username, time.UnixMilli(expireTime).Format("2006-01-02 15:04:05")), nil
+}
+
+func telegramSetDeleted(args []string, deleted int64) (string, error) {
+	if len(args) < 1 {
+		return "", errors.New("usage: /enable <username>")
+	}
+	account, err := dao.GetAccount("username = ?", args[0])
+	if err != nil {
+		return "", fmt.Errorf("account %s not exist", args[0])
+	}
+	if deleted == 1 && *account.Role == "admin" {
+		return "", errors.New("the admin account cannot be disabled")
+	}
+	if err = UpdateAccount(entity.Account{
+		BaseEntity: entity.BaseEntity{Id: account.Id},
+		Deleted:    &deleted,
+	}); err != nil {
+		return "", err
+	}
+	if deleted == 1 {
+		return fmt.Sprintf("%s disabled", *account.Username), nil
+	}
+	return fmt.Sprintf("%s enabled", *account.Username), nil
+}
+
+func telegramCreate(args []string) (string, error) {
+	if len(args) < 1 {
+		return "", errors.New("usage: /create <username> [quotaGB] [days] [devices]")
+	}
+	username := args[0]
+	if !telegramUsernameRegexp.MatchString(username) {
+		return "", errors.New("username must be 6-32 letters, digits or symbols")
+	}
+	if ExistAccountUsername(username, 0) {
+		return "", fmt.Errorf("username %s already exists", username)
+	}
+	numbers := []int64{-1, 30, 3}
+	for i := 1; i < len(args) && i <= len(numbers); i++ {
+		value, err := strconv.ParseInt(args[i], 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("argument %s is invalid", args[i])
+		}
+		numbers[i-1] = value
+	}
+	quota := numbers[0]
+	if quota > 0 {
+		quota = quota * 1024 * 1024 * 1024
+	}
+	expireTime := time.Now().Add(time.Duration(numbers[1]) * 24 * time.Hour).UnixMilli()
+	deviceNo := max(numbers[2], 1)
+
+	pass, err := util.RandomString(12)
+	if err != nil {
+		return "", err
+	}
+	conPassSuffix, err := util.RandomString(12)
+	if err != nil {
+		return "", err
+	}
+	passEncrypt := util.SHA224String(pass)
+	conPass := fmt.Sprintf("%s.%s", username, conPassSuffix)
+	var deleted int64 = 0
+	if err = SaveAccount(entity.Account{
+		Username:   &username,
+		Pass:       &passEncrypt,
+		ConPass:    &conPass,
+		Quota:      &quota,
+		ExpireTime: &expireTime,
+		DeviceNo:   &deviceNo,
+		Deleted:    &deleted,
+	}); err != nil {
+		return "", err
+	}
+	account, err := dao.GetAccount("username = ?", username)
+	if err != nil {
+		return "", err
+	}
+	text := fmt.Sprintf("Account %s created\nPassword: %s\nConnection Password: %s\n", username, pass, conPass)
+	subscribeUrl, err := telegramSubscribeUrl(*account.Id)
+	if err != nil {
+		text += fmt.Sprintf("Subscription: %s", err.Error())
+	} else {
+		text += fmt.Sprintf("Subscription: %s", subscribeUrl)
+	}
+	return text, nil
+}
+
+func telegramAdminBindCode(args []string) (string, error) {
+	if len(args) < 1 {
+		return "", errors.New("usage: /bindcode <username>")
+	}
+	account, err := dao.GetAccount("username = ?", args[0])
+	if err != nil {
+		return "", fmt.Errorf("account %s not exist", args[0])
+	}
+	code, expireAt, err := TelegramBindCode(*account.Id)
+	if err != nil {
+		return "", err
+	}
+	text := fmt.Sprintf("Binding code for %s: %s\nExpires at %s\nThe user sends /start %s to this bot",
+		*account.Username, code, time.UnixMilli(expireAt).Format("2006-01-02 15:04:05"), code)
+	if link := TelegramBindLink(code); link != "" {
+		text += fmt.Sprintf("\nOr opens %s", link)
+	}
+	return text, nil
+}
+
+// telegramSubscribeUrl 机器人没有请求 Host，优先使用面板公网地址，否则使用 Hysteria2 ACME 域名和面板端口拼接订阅地址
+func telegramSubscribeUrl(accountId int64) (string, error) {
+	publicUrl, err := GetConfig(constant.HUIPublicUrl)
+	if err != nil {
+		return "", err
+	}
+	if *publicUrl.Value != "" {
+		return Hysteria2SubscribeUrl(accountId, "", "")
+	}
+	hysteria2Config, err := GetHysteria2Config()
+	if err != nil {
+		return "", err
+	}
+	if hysteria2Config.ACME == nil || len(hysteria2Config.ACME.Domains) == 0 {
+		return "", errors.New("unknown panel host, configure a Hysteria2 ACME domain")
+	}
+	port, crtPath, keyPath, err := GetPortAndCert()
+	if err != nil {
+		return "", err
+	}
+	protocol := "http:"
+	if crtPath != "" && keyPath != "" {
+		protocol = "https:"
+	}
+	return Hysteria2SubscribeUrl(accountId, protocol, net.JoinHostPort(hysteria2Config.ACME.Domains[0], strconv.FormatInt(port, 10)))
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"h-ui/dao"
+	"h-ui/model/constant"
+	"h-ui/model/entity"
+	"h-ui/util"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+type reportAccountTraffic struct {
+	username string
+	traffic  int64
+}
+
+func CronTelegramReportDaily() {
+	telegramReport(constant.ReportPeriodDaily)
+}
+
+func CronTelegramReportWeekly() {
+	telegramReport(constant.ReportPeriodWeekly)
+}
+
+func CronTelegramReportMonthly() {
+	telegramReport(constant.ReportPeriodMonthly)
+}
+
+func telegramReport(period string) {
+	text, err := TelegramReportText(period)
+	if err != nil {
+		logrus.Errorf("telegram %s report err: %v", period, err)
+		return
+	}
+	if err = Notify(constant.NotifyEventReport, text); err != nil {
+		logrus.Warnf("telegram %s report send err: %v", period, err)
+	}
+}
+
+// TelegramReportText 根据模板生成报告，并更新该周期的流量快照
+func TelegramReportText(period string) (string, error) {
+	configs, err := dao.ListConfig("key in ?", []string{
+		constant.TelegramReportTopN,
+		constant.TelegramReportExpireDays,
+		constant.TelegramReportText})
+	if err != nil {
+		return "", err
+	}
+	var topN, expireDays int64 = 5, 7
+	var reportText string
+	for _, item := range configs {
+		if item.Value == nil {
+			continue
+		}
+		switch *item.Key {
+		case constant.TelegramReportTopN:
+			if value, err := strconv.ParseInt(*item.Value, 10, 64); err == nil && value > 0 {
+				topN = value
+			}
+		case constant.TelegramReportExpireDays:
+			if value, err := strconv.ParseInt(*item.Value, 10, 64); err == nil && value > 0 {
+				expireDays = value
+			}
+		case constant.TelegramReportText:
+			reportText = *item.Value
+		}
+	}
+	if reportText == "" {
+		return "", errors.New("telegram report text is empty")
+	}
+
+	now := time.Now()
+	periodStart, err := reportPeriodStart(period, now)
+	if err != nil {
+		return "", err
+	}
+
+	// 账户流量是累计值，和上次报告的快照相减得到周期内流量
+	reportSnapshot, err := dao.GetReportSnapshot(period)
+	snapshotTraffic := map[int64]int64{}
+	var snapshotRestarts int64
+	if err == nil {
+		_ = json.Unmarshal([]byte(*reportSnapshot.Traffic), &snapshotTraffic)
+		snapshotRestarts = *reportSnapshot.Restarts
+		periodStart = time.UnixMilli(*reportSnapshot.ReportAt)
+	} else {
+		reportSnapshot = entity.ReportSnapshot{Period: &period}
+	}
+
+	accounts, err := dao.ListAccount(nil, nil)
+	if err != nil {
+		return "", err
+	}
+	currentTraffic := map[int64]int64{}
+	var totalTraffic int64
+	var accountTraffics []reportAccountTraffic
+	var expiring, overQuota []string
+	var newAccounts int64
+	expireEnd := now.Add(time.Duration(expireDays) * 24 * time.Hour).UnixMilli()
+	for _, item := range accounts {
+		used := *item.Upload + *item.Download
+		currentTraffic[*item.Id] = used
+		traffic := used
+		// 流量被重置过时快照值会大于当前值
+		if last, ok := snapshotTraffic[*item.Id]; ok && last <= used {
+			traffic = used - last
+		}
+		totalTraffic += traffic
+		if traffic > 0 {
+			accountTraffics = append(accountTraffics, reportAccountTraffic{username: *item.Username, traffic: traffic})
+		}
+		if item.CreateTime != nil && !item.CreateTime.Before(periodStart) {
+			newAccounts++
+		}
+		if *item.Deleted != 0 {
+			continue
+		}
+		if *item.ExpireTime > now.UnixMilli() && *item.ExpireTime <= expireEnd {
+			expiring = append(expiring, fmt.Sprintf("%s (%s)", *item.Username, time.UnixMilli(*item.ExpireTime).Format("2006-01-02")))
+		}
+		if *item.Quota >= 0 && used > *item.Quota {
+			overQuota = append(overQuota, fmt.Sprintf("%s (%s/%s)", *item.Username, util.FormatBytes(used), util.FormatBytes(*item.Quota)))
+		}
+	}
+	sort.Slice(accountTraffics, func(i, j int) bool {
+		return accountTraffics[i].traffic > accountTraffics[j].traffic
+	})
+	var top []string
+	for i, item := range accountTraffics {
+		if int64(i) >= topN {
+			break
+		}
+		top = append(top, fmt.Sprintf("%d. %s %s", i+1, item.username, util.FormatBytes(item.traffic)))
+	}
+
+	// 重启次数只在进程内计数，面板重启后从 0 开始
+	restarts := int64(atomic.LoadUint64(&hysteria2Restarted))
+	periodRestarts := restarts
+	if snapshotRestarts <= restarts {
+		periodRestarts = restarts - snapshotRestarts
+	}
+
+	hysteria2Status := "Stopped"
+	if Hysteria2IsRunning() {
+		hysteria2Status = fmt.Sprintf("Running, uptime %s", reportDuration(Hysteria2Uptime()))
+	}
+
+	// 系统指标最多保留 7 天，月报的峰值只覆盖最近 7 天
+	var peakDevices int64
+	systemMetrics, err := dao.ListSystemMetric("sample_time >= ?", periodStart.UnixMilli())
+	if err == nil {
+		for _, item := range systemMetrics {
+			peakDevices = max(peakDevices, *item.DeviceTotal)
+		}
+	}
+
+	replacer := strings.NewReplacer(
+		"[period]", reportPeriodTitle(period),
+		"[time]", now.Format("2006-01-02 15:04:05"),
+		"[traffic]", util.FormatBytes(totalTraffic),
+		"[top]", reportLines(top),
+		"[new]", strconv.FormatInt(newAccounts, 10),
+		"[expiring]", reportLines(expiring),
+		"[overQuota]", reportLines(overQuota),
+		"[hysteria2]", hysteria2Status,
+		"[restarts]", strconv.FormatInt(periodRestarts, 10),
+		"[peakDevices]", strconv.FormatInt(peakDevices, 10),
+	)
+	text := replacer.Replace(reportText)
+
+	trafficJson, err := json.Marshal(currentTraffic)
+	if err != nil {
+		return "", err
+	}
+	trafficStr := string(trafficJson)
+	reportAt := now.UnixMilli()
+	reportSnapshot.Traffic = &trafficStr
+	reportSnapshot.Restarts = &restarts
+	reportSnapshot.ReportAt = &reportAt
+	if err = dao.SaveReportSnapshot(reportSnapshot); err != nil {
+		return "", err
+	}
+	return text, nil
+}
+
+func reportPeriodStart(period string, now time.Time) (time.Time, error) {
+	switch period {
+	case constant.ReportPeriodDaily:
+		return now.AddDate(0, 0, -1), nil
+	case constant.ReportPeriodWeekly:
+		return now.AddDate(0, 0, -7), nil
+	case constant.ReportPeriodMonthly:
+		return now.AddDate(0, -1, 0), nil
+	}
+	return now, errors.New("unsupported report period")
+}
+
+func reportPeriodTitle(period string) string {
+	switch period {
+	case constant.ReportPeriodDaily:
+		return "Daily"
+	case constant.ReportPeriodWeekly:
+		return "Weekly"
+	case constant.ReportPeriodMonthly:
+		return "Monthly"
+	}
+	return period
+}
+
+func reportLines(lines []string) string {
+	if len(lines) == 0 {
+		return "None"
+	}
+	return strings.Join(lines, "\n")
+}
+
+func reportDuration(d time.Duration) string {
+	days := int64(d.Hours()) / 24
+	hours := int64(d.Hours()) % 24
+	minutes := int64(d.Minutes()) % 60
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	}
+	return fmt.Sprintf("%dh %dm", hours, minutes)
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/sirupsen/logrus"
+	"h-ui/dao"
+	"h-ui/model/constant"
+	"h-ui/model/entity"
+	"h-ui/util"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const telegramBindCodeTimeout = 10 * time.Minute
+
+type telegramBindCode struct {
+	accountId int64
+	expireAt  time.Time
+}
+
+var telegramBindMutex sync.Mutex
+var telegramBindCodes = map[string]telegramBindCode{}
+
+var telegramUserCommands = []tgbotapi.BotCommand{
+	{Command: "start", Description: "Bind account: /start <code>"},
+	{Command: "me", Description: "Quota and expiry"},
+	{Command: "sub", Description: "Subscription link"},
+	{Command: "unbind", Description: "Unbind account"},
+}
+
+func telegramUserEnabled() bool {
+	config, err := dao.GetConfig("key = ?", constant.TelegramUserEnable)
+	if err != nil || config.Value == nil {
+		return false
+	}
+	return *config.Value == "1"
+}
+
+// TelegramBindCode 生成一次性绑定码，由管理员发给用户
+func TelegramBindCode(accountId int64) (string, int64, error) {
+	if !telegramUserEnabled() {
+		return "", 0, errors.New("telegram user binding is not enabled")
+	}
+	if _, err := dao.GetAccount("id = ?", accountId); err != nil {
+		return "", 0, errors.New("account not exist")
+	}
+	code, err := util.RandomString(16)
+	if err != nil {
+		return "", 0, err
+	}
+	now := time.Now()
+	expireAt := now.Add(telegramBindCodeTimeout)
+	telegramBindMutex.Lock()
+	for key, item := range telegramBindCodes {
+		if item.accountId == accountId || now.After(item.expireAt) {
+			delete(telegramBindCodes, key)
+		}
+	}
+	telegramBindCodes[code] = telegramBindCode{accountId: accountId, expireAt: expireAt}
+	telegramBindMutex.Unlock()
+	return code, expireAt.UnixMilli(), nil
+}
+
+// TelegramBindLink 机器人深度链接，打开后自动发送 /start <code>
+func TelegramBindLink(code string) string {
+	if bot == nil || bot.Self.UserName == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://t.me/%s?start=%s", bot.Self.UserName, code)
+}
+
+func TelegramUnbind(accountId int64) error {
+	return dao.DeleteTelegramBind("account_id = ?", accountId)
+}
+
+func handleUserCommand(update tgbotapi.Update) error {
+	if !telegramUserEnabled() {
+		return nil
+	}
+	chatId := update.Message.Chat.ID
+	args := strings.Fields(update.Message.CommandArguments())
+	var text string
+	var err error
+	switch update.Message.Command() {
+	case "start", "bind":
+		text, err = telegramBind(chatId, args)
+	case "me":
+		text, err = telegramMe(chatId)
+	case "sub":
+		text, err = telegramSub(chatId)
+	case "unbind":
+		if err = dao.DeleteTelegramBind("chat_id = ?", chatId); err == nil {
+			text = "Unbound"
+		}
+	default:
+		return nil
+	}
+	if err != nil {
+		text = err.Error()
+	}
+	return SendWithMessage(chatId, text)
+}
+
+func telegramBind(chatId int64, args []string) (string, error) {
+	if len(args) < 1 {
+		return "", errors.New("send /start <code> with the code from the administrator")
+	}
+	telegramBindMutex.Lock()
+	bindCode, ok := telegramBindCodes[args[0]]
+	delete(telegramBindCodes, args[0])
+	telegramBindMutex.Unlock()
+	if !ok || time.Now().After(bindCode.expireAt) {
+		return "", errors.New("code is invalid or expired")
+	}
+	account, err := dao.GetAccount("id = ?", bindCode.accountId)
+	if err != nil {
+		return "", errors.New("account not exist")
+	}
+	// 一个账户只绑定一个会话，一个会话也只绑定一个账户
+	if err = dao.DeleteTelegramBind("account_id = ? or chat_id = ?", bindCode.accountId, chatId); err != nil {
+		return "", err
+	}
+	if err = dao.SaveTelegramBind(entity.TelegramBind{
+		AccountId: &bindCode.accountId,
+		ChatId:    &chatId,
+	}); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Bound to %s\n/me: quota and expiry\n/sub: subscription link", *account.Username), nil
+}
+
+func telegramBoundAccount(chatId int64) (entity.Account, error) {
+	telegramBind, err := dao.GetTelegramBind("chat_id = ?", chatId)
+	if err != nil {
+		return entity.Account{}, errors.New("not bound, send /start <code> first")
+	}
+	account, err := dao.GetAccount("id = ?", *telegramBind.AccountId)
+	if err != nil {
+		return entity.Account{}, errors.New("account not exist")
+	}
+	return account, nil
+}
+
+func telegramMe(chatId int64) (string, error) {
+	account, err := telegramBoundAccount(chatId)
+	if err != nil {
+		return "", err
+	}
+	var device int64
+	if onlineUsers, err := Hysteria2Online(); err == nil {
+		device = onlineUsers[*account.Username]
+	}
+	return telegramAccountText(account, device), nil
+}
+
+func telegramSub(chatId int64) (string, error) {
+	account, err := telegramBoundAccount(chatId)
+	if err != nil {
+		return "", err
+	}
+	return telegramSubscribeUrl(*account.Id)
+}
+
+// CronTelegramUserWarn 流量达到阈值和即将到期时提醒已绑定的用户
+func CronTelegramUserWarn() {
+	if bot == nil || !telegramUserEnabled() {
+		return
+	}
+	configs, err := dao.ListConfig("key in ?", []string{
+		constant.TelegramUserQuotaWarn,
+		constant.TelegramUserExpireDays})
+	if err != nil {
+		return
+	}
+	var thresholds []int64
+	var expireDays int64 = 3
+	for _, item := range configs {
+		if item.Value == nil {
+			continue
+		}
+		if *item.Key == constant.TelegramUserQuotaWarn {
+			for _, s := range strings.Split(*item.Value, ",") {
+				if value, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64); err == nil && value > 0 {
+					thresholds = append(thresholds, value)
+				}
+			}
+		} else if *item.Key == constant.TelegramUserExpireDays {
+			if value, err := strconv.ParseInt(*item.Value, 10, 64); err == nil {
+				expireDays = value
+			}
+		}
+	}
+	sort.Slice(thresholds, func(i, j int) bool { return thresholds[i] < thresholds[j] })
+
+	telegramBinds, err := dao.ListTelegramBind(nil, nil)
+	if err != nil {
+		return
+	}
+	now := time.Now().UnixMilli()
+	for _, item := range telegramBinds {
+		account, err := dao.GetAccount("id = ?", *item.AccountId)
+		if err != nil || *account.Deleted != 0 {
+			continue
+		}
+		updates := map[string]interface{}{}
+
+		// 流量重置或调高配额后回落到当前达到的阈值
+		if *account.Quota > 0 {
+			used := *account.Upload + *account.Download
+			percent := used * 100 / *account.Quota
+			var reached int64
+			for _, threshold := range thresholds {
+				if percent >= threshold {
+					reached = threshold
+				}
+			}
+			if reached > *item.QuotaWarned {
+				text := fmt.Sprintf("【H UI】\nYou have used %d%% of your traffic quota (%s/%s)",
+					reached, util.FormatBytes(used), util.FormatBytes(*account.Quota))
+				if err = SendWithMessage(*item.ChatId, text); err == nil {
+					updates["quota_warned"] = reached
+				}
+			} else if reached < *item.QuotaWarned {
+				updates["quota_warned"] = reached
+			}
+		}
+
+		// 记录已提醒的到期时间，续期后会重新提醒
+		if expireDays > 0 && *account.ExpireTime > now &&
+			*account.ExpireTime-now <= expireDays*24*time.Hour.Milliseconds() &&
+			*item.ExpireWarned != *account.ExpireTime {
+			text := fmt.Sprintf("【H UI】\nYour account expires at %s",
+				time.UnixMilli(*account.ExpireTime).Format("2006-01-02 15:04:05"))
+			if err = SendWithMessage(*item.ChatId, text); err == nil {
+				updates["expire_warned"] = *account.ExpireTime
+			}
+		}
+
+		if len(updates) > 0 {
+			if err = dao.UpdateTelegramBind([]int64{*item.Id}, updates); err != nil {
+				logrus.Warnf("update telegram bind err: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"h-ui/dao"
+	"h-ui/model/constant"
+	"h-ui/model/dto"
+	"h-ui/model/entity"
+	"h-ui/util"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	webhookMaxAttempts    = 5
+	webhookMaxDeliveryRow = 10000
+)
+
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+var webhookRetryMutex sync.Mutex
+
+type webhookAccount struct {
+	Id         int64  `json:"id"`
+	Username   string `json:"username"`
+	Quota      int64  `json:"quota"`
+	Download   int64  `json:"download"`
+	Upload     int64  `json:"upload"`
+	ExpireTime int64  `json:"expireTime"`
+	DeviceNo   int64  `json:"deviceNo"`
+	Deleted    int64  `json:"deleted"`
+}
+
+type webhookEvent struct {
+	Id      string                 `json:"id"`
+	Event   string                 `json:"event"`
+	Time    int64                  `json:"time"`
+	Account webhookAccount         `json:"account"`
+	Data    map[string]interface{} `json:"data,omitempty"`
+}
+
+// ValidWebhookEvents 校验逗号分隔的事件过滤
+func ValidWebhookEvents(events string) error {
+	for _, item := range strings.Split(events, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" && !util.ArrContain(constant.AccountEvents, item) {
+			return fmt.Errorf("webhook event %s not supported", item)
+		}
+	}
+	return nil
+}
+
+func webhookMatch(webhook entity.Webhook, event string) bool {
+	if webhook.Events == nil || strings.TrimSpace(*webhook.Events) == "" {
+		return true
+	}
+	for _, item := range strings.Split(*webhook.Events, ",") {
+		if strings.TrimSpace(item) == event {
+			return true
+		}
+	}
+	return false
+}
+
+func SaveWebhook(webhook entity.Webhook) error {
+	if err := ValidWebhookEvents(*webhook.Events); err != nil {
+		return err
+	}
+	_, err := dao.SaveWebhook(webhook)
+	return err
+}
+
+func UpdateWebhook(webhook entity.Webhook) error {
+	updates := map[string]interface{}{}
+	if webhook.Name != nil && *webhook.Name != "" {
+		updates["name"] = *webhook.Name
+	}
+	if webhook.Url != nil && *webhook.Url != "" {
+		updates["url"] = *webhook.Url
+	}
+	if webhook.Secret != nil {
+		updates["secret"] = *webhook.Secret
+	}
+	if webhook.Events != nil {
+		if err := ValidWebhookEvents(*webhook.Events); err != nil {
+			return err
+		}
+		updates["events"] = *webhook.Events
+	}
+	if webhook.Enable != nil {
+		updates["enable"] = *webhook.Enable
+	}
+	return dao.UpdateWebhook([]int64{*webhook.Id}, updates)
+}
+
+func DeleteWebhook(ids []int64) error {
+	return dao.DeleteWebhook(ids)
+}
+
+func ListWebhook() ([]entity.Webhook, error) {
+	return dao.ListWebhook(nil, nil)
+}
+
+func PageWebhookDelivery(webhookDeliveryPageDto dto.WebhookDeliveryPageDto) ([]entity.WebhookDelivery, int64, error) {
+	return dao.PageWebhookDelivery(webhookDeliveryPageDto)
+}
+
+// EmitAccountEvent 为订阅了该事件的 webhook 生成投递记录并异步发送
+func EmitAccountEvent(event string, account entity.Account, data map[string]interface{}) {
+	webhooks, err := dao.ListWebhook("enable = 1")
+	if err != nil || len(webhooks) == 0 {
+		return
+	}
+	eventId, err := util.RandomString(16)
+	if err != nil {
+		return
+	}
+	now := time.Now().UnixMilli()
+	payload, err := json.Marshal(webhookEvent{
+		Id:    eventId,
+		Event: event,
+		Time:  now,
+		Account: webhookAccount{
+			Id:         *account.Id,
+			Username:   *account.Username,
+			Quota:      *account.Quota,
+			Download:   *account.Download,
+			Upload:     *account.Upload,
+			ExpireTime: *account.ExpireTime,
+			DeviceNo:   *account.DeviceNo,
+			Deleted:    *account.Deleted,
+		},
+		Data: data,
+	})
+	if err != nil {
+		logrus.Errorf("marshal webhook event err: %v", err)
+		return
+	}
+	payloadStr := string(payload)
+	status := constant.WebhookDeliveryPending
+	for _, webhook := range webhooks {
+		if !webhookMatch(webhook, event) {
+			continue
+		}
+		deliveryId, err := dao.SaveWebhookDelivery(entity.WebhookDelivery{
+			WebhookId: webhook.Id,
+			Event:     &event,
+			Payload:   &payloadStr,
+			Status:    &status,
+			EventAt:   &now,
+		})
+		if err != nil {
+			continue
+		}
+		go func(webhook entity.Webhook) {
+			delivery, err := dao.GetWebhookDelivery("id = ?", deliveryId)
+			if err != nil {
+				return
+			}
+			_ = deliverWebhook(webhook, delivery)
+		}(webhook)
+	}
+}
+
+// EmitAccountEventById 事件发生后重新读取账户
+func EmitAccountEventById(event string, id int64, data map[string]interface{}) {
+	account, err := dao.GetAccount("id = ?", id)
+	if err != nil {
+		return
+	}
+	EmitAccountEvent(event, account, data)
+}
+
+// deliverWebhook 发送一次，失败时按指数退避安排重试
+func deliverWebhook(webhook entity.Webhook, delivery entity.WebhookDelivery) error {
+	attempts := *delivery.Attempts + 1
+	now := time.Now()
+	updates := map[string]interface{}{
+		"attempts":     attempts,
+		"delivered_at": now.UnixMilli(),
+	}
+
+	code, err := postWebhook(webhook, delivery)
+	updates["response_code"] = code
+	if err == nil {
+		updates["status"] = constant.WebhookDeliverySuccess
+		updates["error"] = ""
+		updates["next_retry_at"] = 0
+	} else {
+		updates["error"] = err.Error()
+		if attempts < webhookMaxAttempts {
+			updates["status"] = constant.WebhookDeliveryPending
+			updates["next_retry_at"] = now.Add(time.Duration(1<<(attempts-1)) * time.Minute).UnixMilli()
+		} else {
+			updates["status"] = constant.WebhookDeliveryFailed
+			updates["next_retry_at"] = 0
+		}
+	}
+	if updateErr := dao.UpdateWebhookDelivery([]int64{*delivery.Id}, updates); updateErr != nil {
+		return updateErr
+	}
+	return err
+}
+
+func postWebhook(webhook entity.Webhook, delivery entity.WebhookDelivery) (int64, error) {
+	body := []byte(*delivery.Payload)
+	req, err := http.NewRequest(http.MethodPost, *webhook.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-HUI-Event", *delivery.Event)
+	req.Header.Set("X-HUI-Delivery", strconv.FormatInt(*delivery.Id, 10))
+	if webhook.Secret != nil && *webhook.Secret != "" {
+		req.Header.Set("X-HUI-Signature", webhookSignature(*webhook.Secret, body))
+	}
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return int64(resp.StatusCode), fmt.Errorf("webhook response status %d", resp.StatusCode)
+	}
+	return int64(resp.StatusCode), nil
+}
+
+// ReplayWebhookDelivery 立即重新发送，成功或失败都会更新投递记录
+func ReplayWebhookDelivery(id int64) error {
+	delivery, err := dao.GetWebhookDelivery("id = ?", id)
+	if err != nil {
+		return err
+	}
+	webhook, err := dao.GetWebhook("id = ?", *delivery.WebhookId)
+	if err != nil {
+		return err
+	}
+	// 重放重新计算重试次数
+	var attempts int64 = 0
+	delivery.Attempts = &attempts
+	return deliverWebhook(webhook, delivery)
+}
+
+func CronWebhookRetry() {
+	if !webhookRetryMutex.TryLock() {
+		return
+	}
+	defer webhookRetryMutex.Unlock()
+
+	now := time.Now().UnixMilli()
+	deliveries, err := dao.ListWebhookDelivery("status = ? and next_retry_at <= ?",
+		constant.WebhookDeliveryPending, now)
+	if err != nil {
+		return
+	}
+	webhooks := map[int64]entity.Webhook{}
+	for _, delivery := range deliveries {
+		// 刚生成的记录由 EmitAccountEvent 发送，超过 1 分钟仍未发送的才补发
+		if *delivery.Attempts == 0 && now-*delivery.EventAt < time.Minute.Milliseconds() {
+			continue
+		}
+		webhook, ok := webhooks[*delivery.WebhookId]
+		if !ok {
+			webhook, err = dao.GetWebhook("id = ?", *delivery.WebhookId)
+			if err != nil {
+				_ = dao.UpdateWebhookDelivery([]int64{*delivery.Id}, map[string]interface{}{
+					"status": constant.WebhookDeliveryFailed,
+					"error":  "webhook not exist",
+				})
+				continue
+			}
+			webhooks[*delivery.WebhookId] = webhook
+		}
+		_ = deliverWebhook(webhook, delivery)
+	}
+	_ = dao.TrimWebhookDelivery(webhookMaxDeliveryRow)
+}
@@ -0,0 +1,44 @@
+package util
+
+import (
+	"net"
+	"strings"
+)
+
+// IPInCIDRs 判断 IP 是否在列表中，列表项可以是单个 IP 或 CIDR
+func IPInCIDRs(ip net.IP, cidrs []string) bool {
+	for _, item := range cidrs {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if strings.Contains(item, "/") {
+			_, ipNet, err := net.ParseCIDR(item)
+			if err != nil {
+				continue
+			}
+			if ipNet.Contains(ip) {
+				return true
+			}
+		} else if ip.Equal(net.ParseIP(item)) {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitHostname 去掉端口和 IPv6 的方括号，例如 [2001:db8::1]:8081 返回 2001:db8::1
+func SplitHostname(hostport string) string {
+	if host, _, err := net.SplitHostPort(hostport); err == nil {
+		return host
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(hostport, "["), "]")
+}
+
+// SplitPort 返回监听地址中的端口，例如 :443、[::]:443、0.0.0.0:443 返回 443
+func SplitPort(addr string) string {
+	if _, port, err := net.SplitHostPort(addr); err == nil {
+		return port
+	}
+	return ""
+}
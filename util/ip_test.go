@@ -0,0 +1,33 @@
+package util
+
+import "testing"
+
+func TestSplitHostname(t *testing.T) {
+	cases := map[string]string{
+		"example.com":        "example.com",
+		"example.com:8081":   "example.com",
+		"1.2.3.4:443":        "1.2.3.4",
+		"[2001:db8::1]:8081": "2001:db8::1",
+		"[2001:db8::1]":      "2001:db8::1",
+		"2001:db8::1":        "2001:db8::1",
+	}
+	for hostport, expected := range cases {
+		if actual := SplitHostname(hostport); actual != expected {
+			t.Errorf("%s: expected %s, got %s", hostport, expected, actual)
+		}
+	}
+}
+
+func TestSplitPort(t *testing.T) {
+	cases := map[string]string{
+		":443":        "443",
+		"0.0.0.0:443": "443",
+		"[::]:8443":   "8443",
+		"443":         "",
+	}
+	for addr, expected := range cases {
+		if actual := SplitPort(addr); actual != expected {
+			t.Errorf("%s: expected %s, got %s", addr, expected, actual)
+		}
+	}
+}
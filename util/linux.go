@@ -6,7 +6,9 @@ import (
 	"github.com/shirou/gopsutil/cpu"
 	"github.com/shirou/gopsutil/disk"
 	"github.com/shirou/gopsutil/mem"
+	gopsutilnet "github.com/shirou/gopsutil/net"
 	"github.com/sirupsen/logrus"
+	"h-ui/model/bo"
 	"net"
 	"os"
 	"os/exec"
@@ -81,6 +83,54 @@ func GetDiskPercent() (float64, error) {
 	return value, err
 }
 
+// GetDiskUsages 所有已挂载磁盘的使用情况
+func GetDiskUsages() ([]bo.DiskUsage, error) {
+	parts, err := disk.Partitions(false)
+	if err != nil {
+		return nil, err
+	}
+	var diskUsages []bo.DiskUsage
+	mountpoints := map[string]bool{}
+	for _, part := range parts {
+		if mountpoints[part.Mountpoint] {
+			continue
+		}
+		mountpoints[part.Mountpoint] = true
+		diskInfo, err := disk.Usage(part.Mountpoint)
+		if err != nil || diskInfo.Total == 0 {
+			continue
+		}
+		percent, _ := strconv.ParseFloat(fmt.Sprintf("%.1f", diskInfo.UsedPercent), 64)
+		diskUsages = append(diskUsages, bo.DiskUsage{
+			Mountpoint: part.Mountpoint,
+			Total:      diskInfo.Total,
+			Used:       diskInfo.Used,
+			Percent:    percent,
+		})
+	}
+	return diskUsages, nil
+}
+
+// GetNetCounters 每个网卡的累计收发字节数
+func GetNetCounters() ([]bo.NetCounter, error) {
+	counters, err := gopsutilnet.IOCounters(true)
+	if err != nil {
+		return nil, err
+	}
+	var netCounters []bo.NetCounter
+	for _, counter := range counters {
+		if counter.Name == "lo" {
+			continue
+		}
+		netCounters = append(netCounters, bo.NetCounter{
+			Name:      counter.Name,
+			BytesSent: counter.BytesSent,
+			BytesRecv: counter.BytesRecv,
+		})
+	}
+	return netCounters, nil
+}
+
 func VerifyPort(port string) error {
 	if port != "" {
 		value, err := strconv.ParseInt(port, 10, 64)
@@ -0,0 +1,286 @@
+package util
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"os"
+)
+
+// mmdbMetadataMarker 元数据段的起始标记
+var mmdbMetadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+var errMMDBInvalid = errors.New("mmdb file is invalid")
+
+// MMDB MaxMind DB 格式的只读解析，只支持按 IP 查询
+type MMDB struct {
+	buf        []byte
+	data       []byte
+	nodeCount  uint
+	recordSize uint
+	ipVersion  uint
+	ipv4Start  uint
+}
+
+func OpenMMDB(path string) (*MMDB, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseMMDB(buf)
+}
+
+func ParseMMDB(buf []byte) (*MMDB, error) {
+	index := bytes.LastIndex(buf, mmdbMetadataMarker)
+	if index < 0 {
+		return nil, errMMDBInvalid
+	}
+	value, _, err := mmdbDecode(buf[index+len(mmdbMetadataMarker):], 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	metadata, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errMMDBInvalid
+	}
+	nodeCount, _ := metadata["node_count"].(uint64)
+	recordSize, _ := metadata["record_size"].(uint64)
+	ipVersion, _ := metadata["ip_version"].(uint64)
+	if recordSize != 24 && recordSize != 28 && recordSize != 32 {
+		return nil, fmt.Errorf("mmdb record size %d is not supported", recordSize)
+	}
+	if ipVersion != 4 && ipVersion != 6 {
+		return nil, fmt.Errorf("mmdb ip version %d is not supported", ipVersion)
+	}
+	// 搜索树之后是 16 字节的 0，然后是数据段
+	treeSize := uint(nodeCount) * uint(recordSize) / 4
+	if treeSize+16 > uint(index) {
+		return nil, errMMDBInvalid
+	}
+	db := &MMDB{
+		buf:        buf,
+		data:       buf[treeSize+16 : index],
+		nodeCount:  uint(nodeCount),
+		recordSize: uint(recordSize),
+		ipVersion:  uint(ipVersion),
+	}
+	// IPv6 数据库中 IPv4 地址位于 ::/96 下
+	if db.ipVersion == 6 {
+		for i := 0; i < 96 && db.ipv4Start < db.nodeCount; i++ {
+			db.ipv4Start = db.readNode(db.ipv4Start, 0)
+		}
+	}
+	return db, nil
+}
+
+// readNode 读取节点的左（bit 为 0）或右记录
+func (db *MMDB) readNode(node uint, bit byte) uint {
+	b := db.buf[node*db.recordSize/4:]
+	switch db.recordSize {
+	case 24:
+		if bit == 0 {
+			return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return uint(b[3])<<16 | uint(b[4])<<8 | uint(b[5])
+	case 28:
+		if bit == 0 {
+			return uint(b[3]&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return uint(b[3]&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6])
+	default:
+		if bit == 0 {
+			return uint(binary.BigEndian.Uint32(b[0:4]))
+		}
+		return uint(binary.BigEndian.Uint32(b[4:8]))
+	}
+}
+
+// Lookup 查询 IP 对应的记录，没有记录时返回 nil
+func (db *MMDB) Lookup(ip net.IP) (interface{}, error) {
+	var address []byte
+	node := uint(0)
+	if ip4 := ip.To4(); ip4 != nil {
+		address = ip4
+		node = db.ipv4Start
+	} else if ip16 := ip.To16(); ip16 != nil {
+		if db.ipVersion == 4 {
+			return nil, nil
+		}
+		address = ip16
+	} else {
+		return nil, fmt.Errorf("ip %v is invalid", ip)
+	}
+	for i := 0; i < len(address)*8 && node < db.nodeCount; i++ {
+		node = db.readNode(node, (address[i/8]>>(7-uint(i%8)))&1)
+	}
+	if node <= db.nodeCount {
+		return nil, nil
+	}
+	value, _, err := mmdbDecode(db.data, node-db.nodeCount-16, 0)
+	return value, err
+}
+
+// Country 查询 IP 所在国家的 ISO 代码，兼容 GeoLite2/GeoIP2 Country 和只有 country_code 字段的数据库
+func (db *MMDB) Country(ip net.IP) (string, error) {
+	value, err := db.Lookup(ip)
+	if err != nil {
+		return "", err
+	}
+	record, ok := value.(map[string]interface{})
+	if !ok {
+		return "", nil
+	}
+	for _, key := range []string{"country", "registered_country"} {
+		if country, ok := record[key].(map[string]interface{}); ok {
+			if isoCode, ok := country["iso_code"].(string); ok && isoCode != "" {
+				return isoCode, nil
+			}
+		}
+	}
+	countryCode, _ := record["country_code"].(string)
+	return countryCode, nil
+}
+
+func mmdbUint(b []byte) uint {
+	var value uint
+	for _, item := range b {
+		value = value<<8 | uint(item)
+	}
+	return value
+}
+
+// mmdbDecode 解码 section 中 offset 处的值，返回值和下一个值的位置，指针相对 section 开头
+func mmdbDecode(section []byte, offset uint, depth int) (interface{}, uint, error) {
+	if depth > 32 || offset >= uint(len(section)) {
+		return nil, 0, errMMDBInvalid
+	}
+	ctrl := section[offset]
+	offset++
+	dataType := uint(ctrl >> 5)
+
+	if dataType == 1 {
+		size := uint(ctrl>>3)&3 + 1
+		if offset+size > uint(len(section)) {
+			return nil, 0, errMMDBInvalid
+		}
+		b := section[offset : offset+size]
+		var pointer uint
+		switch size {
+		case 1:
+			pointer = uint(ctrl&7)<<8 | mmdbUint(b)
+		case 2:
+			pointer = (uint(ctrl&7)<<16 | mmdbUint(b)) + 2048
+		case 3:
+			pointer = (uint(ctrl&7)<<24 | mmdbUint(b)) + 526336
+		default:
+			pointer = mmdbUint(b)
+		}
+		value, _, err := mmdbDecode(section, pointer, depth+1)
+		return value, offset + size, err
+	}
+
+	if dataType == 0 {
+		if offset >= uint(len(section)) {
+			return nil, 0, errMMDBInvalid
+		}
+		dataType = 7 + uint(section[offset])
+		offset++
+	}
+
+	size := uint(ctrl & 0x1f)
+	if size >= 29 {
+		n := size - 28
+		if offset+n > uint(len(section)) {
+			return nil, 0, errMMDBInvalid
+		}
+		extra := mmdbUint(section[offset : offset+n])
+		offset += n
+		switch n {
+		case 1:
+			size = 29 + extra
+		case 2:
+			size = 285 + extra
+		default:
+			size = 65821 + extra
+		}
+	}
+
+	switch dataType {
+	case 7:
+		value := make(map[string]interface{}, size)
+		for i := uint(0); i < size; i++ {
+			key, next, err := mmdbDecode(section, offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			keyStr, ok := key.(string)
+			if !ok {
+				return nil, 0, errMMDBInvalid
+			}
+			item, next, err := mmdbDecode(section, next, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			value[keyStr] = item
+			offset = next
+		}
+		return value, offset, nil
+	case 11:
+		value := make([]interface{}, 0, size)
+		for i := uint(0); i < size; i++ {
+			item, next, err := mmdbDecode(section, offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			value = append(value, item)
+			offset = next
+		}
+		return value, offset, nil
+	case 14:
+		return size != 0, offset, nil
+	}
+
+	if offset+size > uint(len(section)) {
+		return nil, 0, errMMDBInvalid
+	}
+	b := section[offset : offset+size]
+	offset += size
+	switch dataType {
+	case 2:
+		return string(b), offset, nil
+	case 3:
+		if size != 8 {
+			return nil, 0, errMMDBInvalid
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), offset, nil
+	case 4:
+		return append([]byte{}, b...), offset, nil
+	case 5, 6, 9:
+		if size > 8 {
+			return nil, 0, errMMDBInvalid
+		}
+		var value uint64
+		for _, item := range b {
+			value = value<<8 | uint64(item)
+		}
+		return value, offset, nil
+	case 8:
+		if size > 4 {
+			return nil, 0, errMMDBInvalid
+		}
+		return int32(uint32(mmdbUint(b))), offset, nil
+	case 10:
+		return new(big.Int).SetBytes(b), offset, nil
+	case 15:
+		if size != 4 {
+			return nil, 0, errMMDBInvalid
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(b)), offset, nil
+	default:
+		return nil, 0, fmt.Errorf("mmdb data type %d is not supported", dataType)
+	}
+}
@@ -0,0 +1,85 @@
+package util
+
+import (
+	"net"
+	"testing"
+)
+
+// mmdbString 长度小于 29 的字符串
+func mmdbString(value string) []byte {
+	return append([]byte{2<<5 | byte(len(value))}, value...)
+}
+
+func mmdbMap(pairs ...[]byte) []byte {
+	b := []byte{7<<5 | byte(len(pairs)/2)}
+	for _, item := range pairs {
+		b = append(b, item...)
+	}
+	return b
+}
+
+// testMMDB 只包含 1.0.0.0/8 的 IPv4 数据库，记录为 {"country":{"iso_code":"AU"}}，iso_code 的值使用指针
+func testMMDB(recordSize int) []byte {
+	const nodeCount = 8
+	isoCode := mmdbString("AU")
+	data := append([]byte{}, isoCode...)
+	recordOffset := len(data)
+	data = append(data, mmdbMap(mmdbString("country"), mmdbMap(mmdbString("iso_code"), []byte{1 << 5, 0}))...)
+
+	// 1 的二进制为 00000001，前 7 位走左子树，第 8 位走右记录
+	var tree []byte
+	for node := 0; node < nodeCount; node++ {
+		left, right := node+1, nodeCount
+		if node == nodeCount-1 {
+			left, right = nodeCount, nodeCount+16+recordOffset
+		}
+		switch recordSize {
+		case 24:
+			tree = append(tree, byte(left>>16), byte(left>>8), byte(left), byte(right>>16), byte(right>>8), byte(right))
+		case 28:
+			tree = append(tree, byte(left>>16), byte(left>>8), byte(left),
+				byte(left>>20)&0xF0|byte(right>>24)&0x0F, byte(right>>16), byte(right>>8), byte(right))
+		case 32:
+			tree = append(tree, byte(left>>24), byte(left>>16), byte(left>>8), byte(left),
+				byte(right>>24), byte(right>>16), byte(right>>8), byte(right))
+		}
+	}
+
+	buf := append(tree, make([]byte, 16)...)
+	buf = append(buf, data...)
+	buf = append(buf, mmdbMetadataMarker...)
+	buf = append(buf, mmdbMap(
+		mmdbString("node_count"), []byte{6<<5 | 1, nodeCount},
+		mmdbString("record_size"), []byte{5<<5 | 1, byte(recordSize)},
+		mmdbString("ip_version"), []byte{5<<5 | 1, 4},
+	)...)
+	return buf
+}
+
+func TestMMDBCountry(t *testing.T) {
+	for _, recordSize := range []int{24, 28, 32} {
+		db, err := ParseMMDB(testMMDB(recordSize))
+		if err != nil {
+			t.Fatalf("record size %d: %v", recordSize, err)
+		}
+		cases := map[string]string{
+			"1.2.3.4":     "AU",
+			"1.255.0.1":   "AU",
+			"2.0.0.1":     "",
+			"0.1.2.3":     "",
+			"2001:db8::1": "",
+		}
+		for ip, expected := range cases {
+			actual, err := db.Country(net.ParseIP(ip))
+			if err != nil {
+				t.Fatalf("record size %d, %s: %v", recordSize, ip, err)
+			}
+			if actual != expected {
+				t.Errorf("record size %d, %s: expected %q, got %q", recordSize, ip, expected, actual)
+			}
+		}
+	}
+	if _, err := ParseMMDB([]byte("not a mmdb file")); err == nil {
+		t.Error("expected invalid mmdb error")
+	}
+}
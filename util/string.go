@@ -1,6 +1,9 @@
 package util
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 func CompareVersion(version1, version2 string) int {
 	v1 := strings.Split(version1, ".")
@@ -34,3 +37,17 @@ func CompareVersion(version1, version2 string) int {
 	// The version number is exactly the same
 	return 0
 }
+
+// FormatBytes 流量转为可读字符串
+func FormatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit && exp < 4; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.2f %cB", float64(bytes)/float64(div), "KMGTP"[exp])
+}